@@ -0,0 +1,170 @@
+package anim
+
+import "math"
+
+// Easing reshapes progress through an animation, it maps 0 to 0 and 1 to 1.
+// Some, like the back and elastic curves, overshoot in between.
+type Easing func(t float32) float32
+
+// Lerp linearly interpolates between a and b
+func Lerp(a, b, pct float32) float32 {
+	return a + pct*(b-a)
+}
+
+// Linear moves at a constant speed
+func Linear(t float32) float32 {
+	return t
+}
+
+// InQuad starts slow and speeds up
+func InQuad(t float32) float32 {
+	return t * t
+}
+
+// OutQuad starts fast and slows down
+func OutQuad(t float32) float32 {
+	return 1 - (1-t)*(1-t)
+}
+
+// InOutQuad speeds up and then slows down
+func InOutQuad(t float32) float32 {
+	return inOut(InQuad, t)
+}
+
+// InCubic starts slow and speeds up, more sharply than InQuad
+func InCubic(t float32) float32 {
+	return t * t * t
+}
+
+// OutCubic starts fast and slows down, more sharply than OutQuad
+func OutCubic(t float32) float32 {
+	return out(InCubic, t)
+}
+
+// InOutCubic speeds up and then slows down
+func InOutCubic(t float32) float32 {
+	return inOut(InCubic, t)
+}
+
+// InQuart starts slow and speeds up, more sharply than InCubic
+func InQuart(t float32) float32 {
+	return t * t * t * t
+}
+
+// OutQuart starts fast and slows down, more sharply than OutCubic
+func OutQuart(t float32) float32 {
+	return out(InQuart, t)
+}
+
+// InOutQuart speeds up and then slows down
+func InOutQuart(t float32) float32 {
+	return inOut(InQuart, t)
+}
+
+// InSine starts slow and speeds up following a quarter sine wave
+func InSine(t float32) float32 {
+	return 1 - float32(math.Cos(float64(t)*math.Pi/2))
+}
+
+// OutSine starts fast and slows down following a quarter sine wave
+func OutSine(t float32) float32 {
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+
+// InOutSine speeds up and then slows down following half a sine wave
+func InOutSine(t float32) float32 {
+	return -(float32(math.Cos(float64(t)*math.Pi)) - 1) / 2
+}
+
+// InExpo barely moves and then shoots to the end
+func InExpo(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return float32(math.Pow(2, 10*float64(t)-10))
+}
+
+// OutExpo shoots off and then creeps to the end
+func OutExpo(t float32) float32 {
+	return out(InExpo, t)
+}
+
+// InOutExpo creeps, shoots across the middle and creeps again
+func InOutExpo(t float32) float32 {
+	return inOut(InExpo, t)
+}
+
+// InBack pulls back a little before going forwards
+func InBack(t float32) float32 {
+	const overshoot = 1.70158
+	return t * t * ((overshoot+1)*t - overshoot)
+}
+
+// OutBack overshoots the end a little and comes back
+func OutBack(t float32) float32 {
+	return out(InBack, t)
+}
+
+// InOutBack pulls back at the start and overshoots at the end
+func InOutBack(t float32) float32 {
+	return inOut(InBack, t)
+}
+
+// InElastic wobbles with growing swings before snapping to the end
+func InElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	const period = 0.3
+	return -float32(math.Pow(2, 10*float64(t-1)) * math.Sin((float64(t-1)-period/4)*2*math.Pi/period))
+}
+
+// OutElastic snaps to the end and wobbles there like a spring
+func OutElastic(t float32) float32 {
+	return out(InElastic, t)
+}
+
+// InOutElastic wobbles at both ends
+func InOutElastic(t float32) float32 {
+	return inOut(InElastic, t)
+}
+
+// OutBounce drops to the end and bounces a few times like a ball
+func OutBounce(t float32) float32 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	}
+	t -= 2.625 / d
+	return n*t*t + 0.984375
+}
+
+// InBounce bounces a few times with growing height before leaving the start
+func InBounce(t float32) float32 {
+	return out(OutBounce, t)
+}
+
+// InOutBounce bounces at both ends
+func InOutBounce(t float32) float32 {
+	return inOut(InBounce, t)
+}
+
+// out plays an ease in backwards to make the matching ease out
+func out(in Easing, t float32) float32 {
+	return 1 - in(1-t)
+}
+
+// inOut plays an ease in for the first half and its ease out for the second
+func inOut(in Easing, t float32) float32 {
+	if t < 0.5 {
+		return in(t*2) / 2
+	}
+	return 1 - in((1-t)*2)/2
+}
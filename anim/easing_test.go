@@ -0,0 +1,33 @@
+package anim
+
+import "testing"
+
+var easings = []struct {
+	name   string
+	easing Easing
+}{
+	{"Linear", Linear},
+	{"InQuad", InQuad}, {"OutQuad", OutQuad}, {"InOutQuad", InOutQuad},
+	{"InCubic", InCubic}, {"OutCubic", OutCubic}, {"InOutCubic", InOutCubic},
+	{"InQuart", InQuart}, {"OutQuart", OutQuart}, {"InOutQuart", InOutQuart},
+	{"InSine", InSine}, {"OutSine", OutSine}, {"InOutSine", InOutSine},
+	{"InExpo", InExpo}, {"OutExpo", OutExpo}, {"InOutExpo", InOutExpo},
+	{"InBack", InBack}, {"OutBack", OutBack}, {"InOutBack", InOutBack},
+	{"InElastic", InElastic}, {"OutElastic", OutElastic}, {"InOutElastic", InOutElastic},
+	{"InBounce", InBounce}, {"OutBounce", OutBounce}, {"InOutBounce", InOutBounce},
+}
+
+func TestEasingEndpoints(t *testing.T) {
+	for _, e := range easings {
+		if v := e.easing(0); !near(v, 0) {
+			t.Errorf("%s(0) is %v, want 0", e.name, v)
+		}
+		if v := e.easing(1); !near(v, 1) {
+			t.Errorf("%s(1) is %v, want 1", e.name, v)
+		}
+		// the in-out curves are symmetric, so they're halfway at the middle
+		if v := e.easing(0.5); e.name[:5] == "InOut" && !near(v, 0.5) {
+			t.Errorf("%s(0.5) is %v, want 0.5", e.name, v)
+		}
+	}
+}
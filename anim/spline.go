@@ -0,0 +1,135 @@
+package anim
+
+import (
+	"sort"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Curve is a path through space, t runs from 0 at the start to 1 at the end
+type Curve interface {
+	At(t float32) vector3.Vector3
+}
+
+// Bezier is a curve pulled towards its control points, it passes through the first
+// and last. Four points make the usual cubic Bezier.
+type Bezier struct {
+	Points []vector3.Vector3
+}
+
+// At returns the point t along the curve, worked out with de Casteljau's algorithm
+func (b *Bezier) At(t float32) vector3.Vector3 {
+	if len(b.Points) == 0 {
+		return vector3.Vector3{}
+	}
+	// repeatedly lerp between neighbours until one point is left
+	points := make([]vector3.Vector3, len(b.Points))
+	copy(points, b.Points)
+	for n := len(points) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			points[i] = vector3.Lerp(points[i], points[i+1], t)
+		}
+	}
+	return points[0]
+}
+
+// CatmullRom is a smooth curve through every one of its points. Each point gets an
+// equal share of t, so use ArcLength if the points aren't evenly spaced.
+type CatmullRom struct {
+	Points []vector3.Vector3
+}
+
+// At returns the point t along the curve
+func (c *CatmullRom) At(t float32) vector3.Vector3 {
+	n := len(c.Points)
+	switch {
+	case n == 0:
+		return vector3.Vector3{}
+	case n == 1 || t <= 0:
+		return c.Points[0]
+	case t >= 1:
+		return c.Points[n-1]
+	}
+
+	segments := float32(n - 1)
+	i := int(t * segments)
+	local := t*segments - float32(i)
+
+	// the ends repeat their point so the curve still starts and stops on them
+	p0 := c.Points[clampIndex(i-1, n)]
+	p1 := c.Points[i]
+	p2 := c.Points[clampIndex(i+1, n)]
+	p3 := c.Points[clampIndex(i+2, n)]
+
+	// the cubic's coefficients, from the constant term up
+	c0 := p1.Mult(2)
+	c1 := p2.Sub(p0)
+	c2 := p0.Mult(2).Sub(p1.Mult(5)).Add(p2.Mult(4)).Sub(p3)
+	c3 := p1.Sub(p2).Mult(3).Add(p3).Sub(p0)
+	return c0.Add(c1.Mult(local)).Add(c2.Mult(local * local)).Add(c3.Mult(local * local * local)).Mult(0.5)
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// ArcLength walks a curve at a constant speed. Curves usually bunch up where their
+// control points do, ArcLength measures the curve once and evens that out.
+type ArcLength struct {
+	curve Curve
+	// lengths[i] is the distance along the curve at t = i / (len(lengths)-1)
+	lengths []float32
+}
+
+// NewArcLength measures curve by splitting it into samples straight pieces, more
+// samples are more accurate
+func NewArcLength(curve Curve, samples int) *ArcLength {
+	if samples < 1 {
+		samples = 1
+	}
+	lengths := make([]float32, samples+1)
+	prev := curve.At(0)
+	for i := 1; i <= samples; i++ {
+		p := curve.At(float32(i) / float32(samples))
+		lengths[i] = lengths[i-1] + vector3.Distance(prev, p)
+		prev = p
+	}
+	return &ArcLength{curve, lengths}
+}
+
+// Length returns the length of the curve
+func (a *ArcLength) Length() float32 {
+	return a.lengths[len(a.lengths)-1]
+}
+
+// T returns the curve's own t for the point distance along it
+func (a *ArcLength) T(distance float32) float32 {
+	samples := len(a.lengths) - 1
+	if distance <= 0 || a.Length() == 0 {
+		return 0
+	}
+	if distance >= a.Length() {
+		return 1
+	}
+	i := sort.Search(len(a.lengths), func(i int) bool { return a.lengths[i] >= distance })
+	start, end := a.lengths[i-1], a.lengths[i]
+	pct := (distance - start) / (end - start)
+	return (float32(i-1) + pct) / float32(samples)
+}
+
+// AtDistance returns the point distance along the curve
+func (a *ArcLength) AtDistance(distance float32) vector3.Vector3 {
+	return a.curve.At(a.T(distance))
+}
+
+// At returns the point u of the way along the curve by distance, so equal steps
+// of u cover equal distances
+func (a *ArcLength) At(u float32) vector3.Vector3 {
+	return a.AtDistance(u * a.Length())
+}
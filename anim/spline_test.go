@@ -0,0 +1,85 @@
+package anim
+
+import (
+	"testing"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+func nearVec(a, b vector3.Vector3) bool {
+	return a.Sub(b).Length() < 1e-4
+}
+
+// uneven points bunch up at the start and spread out towards the end
+var uneven = []vector3.Vector3{{X: 0}, {X: 0.5}, {X: 1, Y: 0.2}, {X: 6, Y: 1}, {X: 10, Y: 8}, {X: 11, Y: 8}}
+
+func TestBezierEndpoints(t *testing.T) {
+	b := &Bezier{Points: uneven}
+	if p := b.At(0); !nearVec(p, uneven[0]) {
+		t.Errorf("starts at %v, want %v", p, uneven[0])
+	}
+	if p := b.At(1); !nearVec(p, uneven[len(uneven)-1]) {
+		t.Errorf("ends at %v, want %v", p, uneven[len(uneven)-1])
+	}
+	// a two point Bezier is a straight line
+	line := &Bezier{Points: []vector3.Vector3{{X: 0}, {X: 4, Y: 2}}}
+	if p := line.At(0.25); !nearVec(p, vector3.Vector3{X: 1, Y: 0.5}) {
+		t.Errorf("line at a quarter is %v, want 1, 0.5", p)
+	}
+}
+
+func TestCatmullRomPassesThroughPoints(t *testing.T) {
+	c := &CatmullRom{Points: uneven}
+	segments := float32(len(uneven) - 1)
+	for i, want := range uneven {
+		if p := c.At(float32(i) / segments); !nearVec(p, want) {
+			t.Errorf("at point %d the curve is at %v, want %v", i, p, want)
+		}
+	}
+	if p := c.At(-1); p != uneven[0] {
+		t.Errorf("before the start is %v, want %v", p, uneven[0])
+	}
+	if p := c.At(2); p != uneven[len(uneven)-1] {
+		t.Errorf("past the end is %v, want %v", p, uneven[len(uneven)-1])
+	}
+}
+
+func TestArcLengthConstantSpeed(t *testing.T) {
+	for _, curve := range []Curve{&CatmullRom{Points: uneven}, &Bezier{Points: uneven}} {
+		a := NewArcLength(curve, 1000)
+		const steps = 20
+		want := a.Length() / steps
+		for i := 0; i < steps; i++ {
+			// walk each step in small pieces so bends count towards its length
+			var d float32
+			prev := a.At(float32(i) / steps)
+			for j := 1; j <= 50; j++ {
+				p := a.At((float32(i) + float32(j)/50) / steps)
+				d += vector3.Distance(prev, p)
+				prev = p
+			}
+			if d < want*0.98 || d > want*1.02 {
+				t.Errorf("%T: step %d covered %v, want about %v", curve, i, d, want)
+			}
+		}
+		if p := a.At(1); !nearVec(p, curve.At(1)) {
+			t.Errorf("%T: arc length ends at %v, the curve at %v", curve, p, curve.At(1))
+		}
+	}
+}
+
+func TestArcLengthZeroLength(t *testing.T) {
+	p := vector3.Vector3{X: 3, Y: 4, Z: 5}
+	a := NewArcLength(&CatmullRom{Points: []vector3.Vector3{p, p, p}}, 0)
+	if a.Length() != 0 {
+		t.Errorf("length is %v, want 0", a.Length())
+	}
+	for _, u := range []float32{0, 0.5, 1} {
+		if got := a.At(u); got != p {
+			t.Errorf("at %v the point is %v, want %v", u, got, p)
+		}
+		if tt := a.T(u); tt != 0 {
+			t.Errorf("t for distance %v is %v, want 0", u, tt)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package anim
+
+import "github.com/sabith-th/games_with_go/vector3"
+
+// Tween animates a value over time. Make them with a Manager and set them up with
+// the chainable methods before the next Update.
+type Tween struct {
+	duration   float32
+	delay      float32
+	elapsed    float32
+	easing     Easing
+	onComplete func()
+	next       *Tween
+	manager    *Manager
+
+	started  bool
+	done     bool
+	canceled bool
+
+	// start captures the values to animate from, apply sets the value pct of the way
+	start func()
+	apply func(pct float32)
+}
+
+// Ease sets the easing curve, the default is Linear
+func (t *Tween) Ease(easing Easing) *Tween {
+	t.easing = easing
+	return t
+}
+
+// Delay waits seconds before the tween starts
+func (t *Tween) Delay(seconds float32) *Tween {
+	t.delay = seconds
+	return t
+}
+
+// OnComplete calls f when the tween finishes, it isn't called if the tween is canceled
+func (t *Tween) OnComplete(f func()) *Tween {
+	t.onComplete = f
+	return t
+}
+
+// Then starts next when t finishes and returns next, so sequences can be chained.
+// next must come from the same manager and must not have been updated yet.
+func (t *Tween) Then(next *Tween) *Tween {
+	next.manager.remove(next)
+	t.next = next
+	return next
+}
+
+// Done reports whether the tween has finished or been canceled
+func (t *Tween) Done() bool {
+	return t.done
+}
+
+// Cancel stops the tween where it is, anything sequenced after it won't start
+func (t *Tween) Cancel() {
+	t.canceled = true
+	t.done = true
+}
+
+// update advances the tween and returns the time left over once it finished
+func (t *Tween) update(elapsedTime float32) float32 {
+	if t.delay > 0 {
+		t.delay -= elapsedTime
+		if t.delay > 0 {
+			return 0
+		}
+		elapsedTime = -t.delay
+		t.delay = 0
+	}
+	if !t.started {
+		t.started = true
+		if t.start != nil {
+			t.start()
+		}
+	}
+
+	t.elapsed += elapsedTime
+	pct := float32(1)
+	if t.duration > 0 && t.elapsed < t.duration {
+		pct = t.elapsed / t.duration
+	}
+	t.apply(t.easing(pct))
+
+	if pct < 1 {
+		return 0
+	}
+	t.done = true
+	if t.onComplete != nil {
+		t.onComplete()
+	}
+	return t.elapsed - t.duration
+}
+
+// Manager runs tweens from the game loop
+type Manager struct {
+	tweens []*Tween
+	// updating is the tweens Update is working through, so callbacks can clear them
+	updating []*Tween
+}
+
+func (m *Manager) add(duration float32, start func(), apply func(pct float32)) *Tween {
+	t := &Tween{duration: duration, easing: Linear, manager: m, start: start, apply: apply}
+	m.tweens = append(m.tweens, t)
+	return t
+}
+
+func (m *Manager) remove(t *Tween) {
+	for i, other := range m.tweens {
+		if other == t {
+			m.tweens = append(m.tweens[:i], m.tweens[i+1:]...)
+			return
+		}
+	}
+}
+
+// To animates target from whatever it is when the tween starts to value
+func (m *Manager) To(target *float32, value, duration float32) *Tween {
+	var from float32
+	return m.add(duration,
+		func() { from = *target },
+		func(pct float32) { *target = Lerp(from, value, pct) })
+}
+
+// ToVector3 animates target from whatever it is when the tween starts to value
+func (m *Manager) ToVector3(target *vector3.Vector3, value vector3.Vector3, duration float32) *Tween {
+	var from vector3.Vector3
+	return m.add(duration,
+		func() { from = *target },
+		func(pct float32) { *target = vector3.Lerp(from, value, pct) })
+}
+
+// Along moves target along curve, wrap the curve in an ArcLength for a steady speed
+func (m *Manager) Along(target *vector3.Vector3, curve Curve, duration float32) *Tween {
+	return m.add(duration, nil, func(pct float32) { *target = curve.At(pct) })
+}
+
+// Func calls f with the eased progress from 0 to 1, for anything To doesn't cover
+func (m *Manager) Func(f func(pct float32), duration float32) *Tween {
+	return m.add(duration, nil, f)
+}
+
+// Wait does nothing for duration, it's useful as a pause in a sequence
+func (m *Manager) Wait(duration float32) *Tween {
+	return m.add(duration, nil, func(pct float32) {})
+}
+
+// Update advances every running tween by elapsedTime seconds
+func (m *Manager) Update(elapsedTime float32) {
+	// tweens made by callbacks during this update start on the next one
+	tweens := m.tweens
+	m.tweens = nil
+	m.updating = tweens
+
+	var running []*Tween
+	for i, t := range tweens {
+		if t.canceled {
+			continue
+		}
+		leftOver := t.update(elapsedTime)
+		// pass on time a finished tween didn't use to the next in its sequence
+		for t.done && !t.canceled && t.next != nil {
+			t = t.next
+			tweens[i] = t
+			if t.canceled {
+				break
+			}
+			leftOver = t.update(leftOver)
+		}
+		if !t.done {
+			running = append(running, t)
+		}
+	}
+	m.updating = nil
+
+	// a callback may have canceled tweens that had already been updated
+	kept := running[:0]
+	for _, t := range running {
+		if !t.canceled {
+			kept = append(kept, t)
+		}
+	}
+	m.tweens = append(kept, m.tweens...)
+}
+
+// Len returns how many tweens are running or waiting to start
+func (m *Manager) Len() int {
+	return len(m.tweens)
+}
+
+// Clear cancels every tween, including ones an Update in progress hasn't reached yet
+func (m *Manager) Clear() {
+	for _, t := range m.tweens {
+		t.Cancel()
+	}
+	for _, t := range m.updating {
+		t.Cancel()
+	}
+	m.tweens = nil
+}
@@ -0,0 +1,93 @@
+package anim
+
+import "testing"
+
+func near(a, b float32) bool {
+	d := a - b
+	return d > -1e-5 && d < 1e-5
+}
+
+func TestTweenTo(t *testing.T) {
+	m := &Manager{}
+	var x float32 = 2
+	completed := false
+	m.To(&x, 10, 1).OnComplete(func() { completed = true })
+
+	m.Update(0.25)
+	if !near(x, 4) {
+		t.Errorf("x is %v a quarter of the way, want 4", x)
+	}
+	m.Update(1)
+	if x != 10 || !completed || m.Len() != 0 {
+		t.Errorf("x is %v, completed %v, %d tweens left after the end", x, completed, m.Len())
+	}
+}
+
+func TestSequencePassesOnLeftOverTime(t *testing.T) {
+	m := &Manager{}
+	var x, y float32
+	m.To(&x, 1, 0.5).Then(m.To(&y, 1, 1))
+
+	m.Update(0.75)
+	if x != 1 || !near(y, 0.25) {
+		t.Errorf("x is %v and y is %v, want 1 and 0.25", x, y)
+	}
+	if m.Len() != 1 {
+		t.Errorf("%d tweens running, want 1", m.Len())
+	}
+}
+
+func TestClearDuringUpdate(t *testing.T) {
+	m := &Manager{}
+	var a, b, c, d float32
+	// the first tween finishes and clears the manager before the others are updated
+	m.To(&a, 1, 0.1).OnComplete(m.Clear)
+	m.To(&b, 1, 1)
+	m.To(&c, 1, 0.1).Then(m.To(&d, 1, 1))
+
+	m.Update(0.2)
+	if a != 1 {
+		t.Errorf("a is %v, want 1", a)
+	}
+	if b != 0 || c != 0 || d != 0 {
+		t.Errorf("cleared tweens still ran: b %v, c %v, d %v", b, c, d)
+	}
+	if m.Len() != 0 {
+		t.Errorf("%d tweens left after Clear", m.Len())
+	}
+	m.Update(1)
+	if b != 0 || d != 0 {
+		t.Errorf("cleared tweens ran on the next update: b %v, d %v", b, d)
+	}
+}
+
+func TestClearFromLaterTween(t *testing.T) {
+	m := &Manager{}
+	var a, b, c float32
+	// a is updated and still running when c's callback clears everything
+	first := m.To(&a, 1, 1)
+	second := m.To(&b, 1, 0.1).Then(m.To(&c, 1, 0.1).OnComplete(m.Clear))
+
+	m.Update(0.5)
+	if !first.Done() || !second.Done() || m.Len() != 0 {
+		t.Errorf("tweens still running after Clear: %d", m.Len())
+	}
+	m.Update(1)
+	if !near(a, 0.5) {
+		t.Errorf("a is %v, want it to stop at 0.5", a)
+	}
+}
+
+func TestTweensMadeInCallbacksStartNextUpdate(t *testing.T) {
+	m := &Manager{}
+	var x, y float32
+	m.To(&x, 1, 0.1).OnComplete(func() { m.To(&y, 1, 1) })
+	m.Update(0.5)
+	if y != 0 || m.Len() != 1 {
+		t.Errorf("y is %v with %d tweens, want 0 and 1", y, m.Len())
+	}
+	m.Update(0.5)
+	if !near(y, 0.5) {
+		t.Errorf("y is %v, want 0.5", y)
+	}
+}
@@ -1,62 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"image/png"
 	"os"
 	"time"
 
+	"github.com/sabith-th/games_with_go/anim"
+	"github.com/sabith-th/games_with_go/colors"
 	"github.com/sabith-th/games_with_go/noise"
+	"github.com/sabith-th/games_with_go/vector2"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 const winWidth, winHeight int = 800, 600
 
 type texture struct {
-	pixels []byte
-	position
+	pixels      []byte
+	pos         vector2.Vector2
 	w, h, pitch int
 	scale       float32
 }
 
-type rgba struct {
-	r, g, b byte
-}
-
-type position struct {
-	x, y float32
-}
-
-func lerp(b1, b2 byte, pct float32) byte {
-	return byte(float32(b1) + pct*(float32(b2)-float32(b1)))
-}
-
-func rgbalerp(c1, c2 rgba, pct float32) rgba {
-	return rgba{lerp(c1.r, c2.r, pct), lerp(c1.g, c2.g, pct), lerp(c1.b, c2.b, pct)}
-}
-
-func getGradient(c1, c2 rgba) []rgba {
-	result := make([]rgba, 256)
-	for i := range result {
-		pct := float32(i) / float32(255)
-		result[i] = rgbalerp(c1, c2, pct)
-	}
-	return result
-}
-
-func getDualGradient(c1, c2, c3, c4 rgba) []rgba {
-	result := make([]rgba, 256)
-	for i := range result {
-		pct := float32(i) / float32(255)
-		if pct < 0.5 {
-			result[i] = rgbalerp(c1, c2, pct*float32(2))
-		} else {
-			result[i] = rgbalerp(c3, c4, pct*float32(1.5)-float32(0.5))
-		}
-	}
-	return result
-}
-
 func clamp(min, max, v int) int {
 	if v < min {
 		v = min
@@ -66,7 +32,7 @@ func clamp(min, max, v int) int {
 	return v
 }
 
-func rescaleAndDraw(noise []float32, min, max float32, gradient []rgba, w, h int) []byte {
+func rescaleAndDraw(noise []float32, min, max float32, gradient []colors.Color, w, h int) []byte {
 	result := make([]byte, w*h*4)
 	scale := 255.0 / (max - min)
 	offset := min * scale
@@ -75,9 +41,9 @@ func rescaleAndDraw(noise []float32, min, max float32, gradient []rgba, w, h int
 		noise[i] = noise[i]*scale - offset
 		c := gradient[clamp(0, 255, int(noise[i]))]
 		p := i * 4
-		result[p] = c.r
-		result[p+1] = c.g
-		result[p+2] = c.b
+		result[p] = c.R
+		result[p+1] = c.G
+		result[p+2] = c.B
 	}
 	return result
 }
@@ -88,12 +54,12 @@ func clear(pixels []byte) {
 	}
 }
 
-func setPixel(x, y int, c rgba, pixels []byte) {
+func setPixel(x, y int, c colors.Color, pixels []byte) {
 	index := (y*winWidth + x) * 4
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
 }
 
@@ -104,12 +70,12 @@ func (tex *texture) drawScaled(scaleX, scaleY float32, pixels []byte) {
 	for y := 0; y < newHeight; y++ {
 		fy := float32(y) / float32(newHeight) * float32(tex.h-1)
 		fyi := int(fy)
-		screenY := int(fy*scaleY) + int(tex.y)
-		screenIndex := screenY*winWidth*4 + int(tex.x)*4
+		screenY := int(fy*scaleY) + int(tex.pos.Y)
+		screenIndex := screenY*winWidth*4 + int(tex.pos.X)*4
 
 		for x := 0; x < newWidth; x++ {
 			fx := float32(x) / float32(newWidth) * float32(tex.w-1)
-			screenX := int(fx*scaleX) + int(tex.x)
+			screenX := int(fx*scaleX) + int(tex.pos.X)
 			if screenIndex < winHeight*winWidth*4 && screenIndex > 0 {
 				if screenX >= 0 && screenX < winWidth && screenY >= 0 && screenY < winHeight {
 					fxi4 := int(fx) * 4
@@ -126,12 +92,8 @@ func (tex *texture) drawScaled(scaleX, scaleY float32, pixels []byte) {
 	}
 }
 
-func flerp(a, b, pct float32) float32 {
-	return a + (b-a)*pct
-}
-
 func blerp(c00, c01, c10, c11, tx, ty float32) float32 {
-	return flerp(flerp(c00, c10, tx), flerp(c01, c11, tx), ty)
+	return anim.Lerp(anim.Lerp(c00, c10, tx), anim.Lerp(c01, c11, tx), ty)
 }
 
 func (tex *texture) drawBilinearScaled(scaleX, scaleY float32, pixels []byte) {
@@ -141,13 +103,13 @@ func (tex *texture) drawBilinearScaled(scaleX, scaleY float32, pixels []byte) {
 	for y := 0; y < newHeight; y++ {
 		fy := float32(y) / float32(newHeight) * float32(tex.h-1)
 		fyi := int(fy)
-		screenY := int(fy*scaleY) + int(tex.y)
-		screenIndex := screenY*winWidth*4 + int(tex.x)*4
+		screenY := int(fy*scaleY) + int(tex.pos.Y)
+		screenIndex := screenY*winWidth*4 + int(tex.pos.X)*4
 		ty := fy - float32(fyi)
 
 		for x := 0; x < newWidth; x++ {
 			fx := float32(x) / float32(newWidth) * float32(tex.w-1)
-			screenX := int(fx*scaleX) + int(tex.x)
+			screenX := int(fx*scaleX) + int(tex.pos.X)
 			if screenIndex < winHeight*winWidth*4 && screenIndex > 0 {
 				if screenX >= 0 && screenX < winWidth && screenY >= 0 && screenY < winHeight {
 					fxi := int(fx)
@@ -177,8 +139,8 @@ func (tex *texture) drawBilinearScaled(scaleX, scaleY float32, pixels []byte) {
 func (tex *texture) draw(pixels []byte) {
 	for y := 0; y < tex.h; y++ {
 		for x := 0; x < tex.w; x++ {
-			screenY := y + int(tex.y)
-			screenX := x + int(tex.x)
+			screenY := y + int(tex.pos.Y)
+			screenX := x + int(tex.pos.X)
 			if screenX >= 0 && screenX < winWidth && screenY >= 0 && screenY < winHeight {
 				texIndex := y*tex.pitch + x*4
 				screenIndex := screenY*winWidth*4 + screenX*4
@@ -194,8 +156,8 @@ func (tex *texture) draw(pixels []byte) {
 func (tex *texture) drawAlpha(pixels []byte) {
 	for y := 0; y < tex.h; y++ {
 		for x := 0; x < tex.w; x++ {
-			screenY := y + int(tex.y)
-			screenX := x + int(tex.x)
+			screenY := y + int(tex.pos.Y)
+			screenX := x + int(tex.pos.X)
 			if screenX >= 0 && screenX < winWidth && screenY >= 0 && screenY < winHeight {
 				texIndex := y*tex.pitch + x*4
 				screenIndex := screenY*winWidth*4 + screenX*4
@@ -256,7 +218,7 @@ func loadBalloons() []texture {
 				bIndex++
 			}
 		}
-		balloonTextures[i] = texture{balloonPixels, position{float32(i * 60), float32(i * 60)}, w, h, w * 4, float32(1 + i)}
+		balloonTextures[i] = texture{balloonPixels, vector2.Vector2{X: float32(i * 60), Y: float32(i * 60)}, w, h, w * 4, float32(1 + i)}
 	}
 	return balloonTextures
 }
@@ -288,10 +250,16 @@ func main() {
 
 	pixels := make([]byte, winWidth*winHeight*4)
 
-	cloudNoise, min, max := noise.MakeNoise(noise.FBM, 0.009, 0.5, 3, 3, winWidth, winHeight)
-	cloudGradient := getGradient(rgba{0, 0, 255}, rgba{255, 255, 255})
-	cloudPixels := rescaleAndDraw(cloudNoise, min, max, cloudGradient, winWidth, winHeight)
-	cloudTexture := texture{cloudPixels, position{0, 0}, winWidth, winHeight, winWidth * 4, float32(1)}
+	cloudNoise, err := noise.MakeNoise(context.Background(), noise.Options{
+		Module: &noise.Simplex{Type: noise.FBM, Frequency: 0.009, Lacunarity: 0.5, Gain: 3, Octaves: 3},
+		Width:  winWidth, Height: winHeight})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	cloudGradient := colors.GetGradient(colors.RGB(0, 0, 255), colors.RGB(255, 255, 255))
+	cloudPixels := rescaleAndDraw(cloudNoise.Noise, cloudNoise.Min, cloudNoise.Max, cloudGradient, winWidth, winHeight)
+	cloudTexture := texture{cloudPixels, vector2.Vector2{}, winWidth, winHeight, winWidth * 4, float32(1)}
 	balloonTextures := loadBalloons()
 	dir := [3]int{1, 1, 1}
 
@@ -309,8 +277,8 @@ func main() {
 
 		for i, tex := range balloonTextures {
 			tex.drawBilinearScaled(tex.scale, tex.scale, pixels)
-			balloonTextures[i].x += float32((i + 1) * dir[i])
-			if balloonTextures[i].x > float32(winWidth-200*(1+i)) || balloonTextures[i].x < 0 {
+			balloonTextures[i].pos.X += float32((i + 1) * dir[i])
+			if balloonTextures[i].pos.X > float32(winWidth-200*(1+i)) || balloonTextures[i].pos.X < 0 {
 				dir[i] = -1 * dir[i]
 			}
 		}
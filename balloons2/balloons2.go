@@ -1,14 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"image/png"
-	"math"
 	"math/rand"
 	"os"
 	"sort"
 	"time"
 
+	"github.com/sabith-th/games_with_go/colors"
+	"github.com/sabith-th/games_with_go/geometry"
 	"github.com/sabith-th/games_with_go/noise"
 	"github.com/sabith-th/games_with_go/vector3"
 	"github.com/veandco/go-sdl2/sdl"
@@ -80,20 +82,52 @@ func (balloon *balloon) getScale() float32 {
 	return (balloon.pos.Z/200 + 1) / 2
 }
 
-func (balloon *balloon) getCircle() (x, y, r float32) {
-	x = balloon.pos.X
-	y = balloon.pos.Y - 30*balloon.getScale()
-	r = float32(balloon.w) / 2 * balloon.getScale()
-	return x, y, r
+func (balloon *balloon) getSphere() geometry.Sphere {
+	scale := balloon.getScale()
+	center := vector3.Vector3{X: balloon.pos.X, Y: balloon.pos.Y - 30*scale, Z: balloon.pos.Z}
+	return geometry.Sphere{Center: center, Radius: float32(balloon.w) / 2 * scale}
+}
+
+// mouseRay returns a ray from the mouse into the screen, balloons with a bigger Z are nearer
+func mouseRay(mouse mouseState) geometry.Ray {
+	origin := vector3.Vector3{X: float32(mouse.x), Y: float32(mouse.y), Z: float32(winDepth) * 2}
+	return geometry.Ray{Origin: origin, Dir: vector3.Vector3{Z: -1}}
+}
+
+// pickBalloon returns the balloon hit by ray that is drawn on top, or nil.
+// The spheres are measured in pixels rather than depth units so they are ranked
+// by Z, the same as the draw order, instead of by where the ray hits them.
+func pickBalloon(balloons []*balloon, ray geometry.Ray) *balloon {
+	var picked *balloon
+	for _, balloon := range balloons {
+		if balloon.exploding {
+			continue
+		}
+		_, hit := ray.IntersectSphere(balloon.getSphere())
+		if hit && (picked == nil || balloon.pos.Z > picked.pos.Z) {
+			picked = balloon
+		}
+	}
+	return picked
 }
 
-func updateBalloons(balloons []*balloon, elapsedTime float32, currentMouseState,
-	prevMouseState mouseState, audioState *audioState) []*balloon {
+func updateBalloons(balloons []*balloon, elapsedTime, totalTime float32, wind *noise.FlowField,
+	currentMouseState, prevMouseState mouseState, audioState *audioState) []*balloon {
 
 	numAnimations := 16
-	balloonClicked := false
 	balloonsExploded := false
 
+	if !prevMouseState.leftButton && currentMouseState.leftButton {
+		balloon := pickBalloon(balloons, mouseRay(currentMouseState))
+		if balloon != nil {
+			sdl.ClearQueuedAudio(audioState.deviceID)
+			sdl.QueueAudio(audioState.deviceID, audioState.explosionBytes)
+			sdl.PauseAudioDevice(audioState.deviceID, false)
+			balloon.exploding = true
+			balloon.explosionStart = time.Now()
+		}
+	}
+
 	for i := len(balloons) - 1; i >= 0; i-- {
 		balloon := balloons[i]
 
@@ -107,36 +141,27 @@ func updateBalloons(balloons []*balloon, elapsedTime float32, currentMouseState,
 			}
 		}
 
-		if !balloonClicked && !prevMouseState.leftButton && currentMouseState.leftButton {
-			x, y, r := balloon.getCircle()
-			mouseX, mouseY := currentMouseState.x, currentMouseState.y
-			xDiff := float32(mouseX) - x
-			yDiff := float32(mouseY) - y
-			dist := float32(math.Sqrt(float64(xDiff*xDiff + yDiff*yDiff)))
-			if dist < r {
-				balloonClicked = true
-				sdl.ClearQueuedAudio(audioState.deviceID)
-				sdl.QueueAudio(audioState.deviceID, audioState.explosionBytes)
-				sdl.PauseAudioDevice(audioState.deviceID, false)
-				balloon.exploding = true
-				balloon.explosionStart = time.Now()
-			}
-		}
-		p := vector3.Add(balloon.pos, vector3.Mult(balloon.dir, elapsedTime))
+		windX, windY, windZ := wind.Curl3(balloon.pos.X, balloon.pos.Y, balloon.pos.Z, totalTime)
+		velocity := vector3.Add(balloon.dir, vector3.Vector3{X: windX, Y: windY, Z: windZ})
+		p := vector3.Add(balloon.pos, vector3.Mult(velocity, elapsedTime))
 
+		// the wind can be stronger than a balloon's own direction so stop at the walls too
 		if p.X < 0 || p.X > float32(winWidth) {
 			balloon.dir.X = -balloon.dir.X
+			p.X = balloon.pos.X
 		}
 
 		if p.Y < 0 || p.Y > float32(winHeight) {
 			balloon.dir.Y = -balloon.dir.Y
+			p.Y = balloon.pos.Y
 		}
 
 		if p.Z < 0 || p.Z > float32(winDepth) {
 			balloon.dir.Z = -balloon.dir.Z
+			p.Z = balloon.pos.Z
 		}
 
-		balloon.pos = vector3.Add(balloon.pos, vector3.Mult(balloon.dir, elapsedTime))
+		balloon.pos = p
 	}
 
 	if balloonsExploded {
@@ -176,27 +201,6 @@ func (balloon *balloon) draw(renderer *sdl.Renderer) {
 	}
 }
 
-type rgba struct {
-	r, g, b byte
-}
-
-func lerp(b1, b2 byte, pct float32) byte {
-	return byte(float32(b1) + pct*(float32(b2)-float32(b1)))
-}
-
-func rgbalerp(c1, c2 rgba, pct float32) rgba {
-	return rgba{lerp(c1.r, c2.r, pct), lerp(c1.g, c2.g, pct), lerp(c1.b, c2.b, pct)}
-}
-
-func getGradient(c1, c2 rgba) []rgba {
-	result := make([]rgba, 256)
-	for i := range result {
-		pct := float32(i) / float32(255)
-		result[i] = rgbalerp(c1, c2, pct)
-	}
-	return result
-}
-
 func clamp(min, max, v int) int {
 	if v < min {
 		v = min
@@ -206,7 +210,7 @@ func clamp(min, max, v int) int {
 	return v
 }
 
-func rescaleAndDraw(noise []float32, min, max float32, gradient []rgba, w, h int) []byte {
+func rescaleAndDraw(noise []float32, min, max float32, gradient []colors.Color, w, h int) []byte {
 	result := make([]byte, w*h*4)
 	scale := 255.0 / (max - min)
 	offset := min * scale
@@ -215,9 +219,9 @@ func rescaleAndDraw(noise []float32, min, max float32, gradient []rgba, w, h int
 		noise[i] = noise[i]*scale - offset
 		c := gradient[clamp(0, 255, int(noise[i]))]
 		p := i * 4
-		result[p] = c.r
-		result[p+1] = c.g
-		result[p+2] = c.b
+		result[p] = c.R
+		result[p+1] = c.G
+		result[p+2] = c.B
 	}
 	return result
 }
@@ -228,12 +232,12 @@ func clear(pixels []byte) {
 	}
 }
 
-func setPixel(x, y int, c rgba, pixels []byte) {
+func setPixel(x, y int, c colors.Color, pixels []byte) {
 	index := (y*winWidth + x) * 4
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
 }
 
@@ -341,13 +345,20 @@ func main() {
 
 	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "1")
 
-	cloudNoise, min, max := noise.MakeNoise(noise.FBM, 0.009, 0.5, 3, 3, winWidth, winHeight)
-	cloudGradient := getGradient(rgba{0, 0, 255}, rgba{255, 255, 255})
-	cloudPixels := rescaleAndDraw(cloudNoise, min, max, cloudGradient, winWidth, winHeight)
+	cloudNoise, err := noise.MakeNoise(context.Background(), noise.Options{
+		Module: &noise.Simplex{Type: noise.FBM, Frequency: 0.009, Lacunarity: 0.5, Gain: 3, Octaves: 3},
+		Width:  winWidth, Height: winHeight})
+	if err != nil {
+		panic(err)
+	}
+	cloudGradient := colors.GetGradient(colors.RGB(0, 0, 255), colors.RGB(255, 255, 255))
+	cloudPixels := rescaleAndDraw(cloudNoise.Noise, cloudNoise.Min, cloudNoise.Max, cloudGradient, winWidth, winHeight)
 	cloudTexture := pixelsToTexture(renderer, cloudPixels, winWidth, winHeight)
 
 	balloons := loadBalloons(renderer, 20)
-	var elapsedTime float32
+	wind := &noise.FlowField{Frequency: 0.004, Lacunarity: 2, Gain: 0.5, Octaves: 2,
+		TimeScale: 0.02, Strength: 0.1}
+	var elapsedTime, totalTime float32
 	currentMouseState := getMouseState()
 	prevMouseState := currentMouseState
 
@@ -360,6 +371,18 @@ func main() {
 			switch e := event.(type) {
 			case *sdl.QuitEvent:
 				return
+			case *sdl.KeyboardEvent:
+				if e.Type == sdl.KEYDOWN {
+					switch e.Keysym.Scancode {
+					case sdl.SCANCODE_EQUALS:
+						wind.Strength += 0.02
+					case sdl.SCANCODE_MINUS:
+						wind.Strength -= 0.02
+						if wind.Strength < 0 {
+							wind.Strength = 0
+						}
+					}
+				}
 			case *sdl.TouchFingerEvent:
 				if e.Type == sdl.FINGERDOWN {
 					touchX := int(e.X * float32(winWidth))
@@ -372,7 +395,7 @@ func main() {
 
 		renderer.Copy(cloudTexture, nil, nil)
 
-		balloons = updateBalloons(balloons, elapsedTime, currentMouseState, prevMouseState, &audioState)
+		balloons = updateBalloons(balloons, elapsedTime, totalTime, wind, currentMouseState, prevMouseState, &audioState)
 
 		sort.Stable(balloonArray(balloons))
 		for _, balloon := range balloons {
@@ -386,6 +409,7 @@ func main() {
 			sdl.Delay(5 - uint32(elapsedTime))
 			elapsedTime = float32(time.Since(frameStart).Seconds() * 1000)
 		}
+		totalTime += elapsedTime
 
 		prevMouseState = currentMouseState
 	}
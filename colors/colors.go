@@ -0,0 +1,259 @@
+package colors
+
+import (
+	"fmt"
+	"math"
+)
+
+// Color is an 8 bit per channel rgba colour
+type Color struct {
+	R, G, B, A byte
+}
+
+// RGB returns an opaque colour
+func RGB(r, g, b byte) Color {
+	return Color{r, g, b, 255}
+}
+
+// Lerp linearly interpolates between two bytes
+func Lerp(b1, b2 byte, pct float32) byte {
+	return byte(float32(b1) + pct*(float32(b2)-float32(b1)))
+}
+
+// LerpColor linearly interpolates each channel of two colours
+func LerpColor(c1, c2 Color, pct float32) Color {
+	return Color{Lerp(c1.R, c2.R, pct), Lerp(c1.G, c2.G, pct), Lerp(c1.B, c2.B, pct), Lerp(c1.A, c2.A, pct)}
+}
+
+// MarshalText returns the colour as #rrggbbaa
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)), nil
+}
+
+// UnmarshalText parses a colour written as #rrggbb or #rrggbbaa, #rrggbb is opaque
+func (c *Color) UnmarshalText(text []byte) error {
+	parsed, err := ParseHex(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// ParseHex parses a colour written as #rrggbb or #rrggbbaa, #rrggbb is opaque
+func ParseHex(s string) (Color, error) {
+	c := Color{A: 255}
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		return c, fmt.Errorf("colors: invalid colour %q", s)
+	}
+	if err != nil {
+		return c, fmt.Errorf("colors: invalid colour %q", s)
+	}
+	return c, nil
+}
+
+// Space is a colour space gradients can be interpolated in
+type Space int
+
+const (
+	// SRGB interpolates the stored sRGB values directly
+	SRGB Space = iota
+	// LinearRGB interpolates light intensity, which keeps mixes from looking too dark
+	LinearRGB
+	// HSV interpolates hue, saturation and value, going the short way round the hue circle
+	HSV
+	// OKLab interpolates in a perceptually uniform space
+	OKLab
+)
+
+var spaceNames = []string{"srgb", "linear", "hsv", "oklab"}
+
+// String returns the name of the colour space
+func (s Space) String() string {
+	if int(s) < len(spaceNames) {
+		return spaceNames[s]
+	}
+	return fmt.Sprintf("Space(%d)", int(s))
+}
+
+// MarshalText returns the name of the colour space
+func (s Space) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses the name of a colour space
+func (s *Space) UnmarshalText(text []byte) error {
+	for i, name := range spaceNames {
+		if name == string(text) {
+			*s = Space(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("colors: unknown colour space %q", text)
+}
+
+// vec is a colour in some space as floats, alpha is always the fourth component
+type vec [4]float64
+
+func (s Space) from(c Color) vec {
+	r := float64(c.R) / 255
+	g := float64(c.G) / 255
+	b := float64(c.B) / 255
+	a := float64(c.A) / 255
+	switch s {
+	case LinearRGB:
+		return vec{toLinear(r), toLinear(g), toLinear(b), a}
+	case HSV:
+		h, sat, v := rgbToHSV(r, g, b)
+		return vec{h, sat, v, a}
+	case OKLab:
+		l, labA, labB := linearToOKLab(toLinear(r), toLinear(g), toLinear(b))
+		return vec{l, labA, labB, a}
+	}
+	return vec{r, g, b, a}
+}
+
+func (s Space) to(v vec) Color {
+	r, g, b := v[0], v[1], v[2]
+	switch s {
+	case LinearRGB:
+		r, g, b = toSRGB(r), toSRGB(g), toSRGB(b)
+	case HSV:
+		r, g, b = hsvToRGB(v[0], v[1], v[2])
+	case OKLab:
+		r, g, b = okLabToLinear(v[0], v[1], v[2])
+		r, g, b = toSRGB(r), toSRGB(g), toSRGB(b)
+	}
+	return Color{toByte(r), toByte(g), toByte(b), toByte(v[3])}
+}
+
+// mix interpolates two colours already converted into this space
+func (s Space) mix(a, b vec, pct float64) vec {
+	var result vec
+	for i := range result {
+		result[i] = a[i] + pct*(b[i]-a[i])
+	}
+	if s == HSV {
+		// grays have no hue, so blends with them keep the other colour's hue
+		// rather than swinging round from red
+		if a[1] == 0 {
+			a[0] = b[0]
+		} else if b[1] == 0 {
+			b[0] = a[0]
+		}
+		// hue is an angle in 0 to 1, take the shortest way round
+		diff := b[0] - a[0]
+		if diff > 0.5 {
+			diff--
+		} else if diff < -0.5 {
+			diff++
+		}
+		result[0] = a[0] + pct*diff
+		result[0] -= math.Floor(result[0])
+	}
+	return result
+}
+
+func toByte(v float64) byte {
+	if v <= 0 {
+		return 0
+	} else if v >= 1 {
+		return 255
+	}
+	return byte(v*255 + 0.5)
+}
+
+func toLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func toSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func rgbToHSV(r, g, b float64) (h, s, v float64) {
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	v = max
+	delta := max - min
+	if max > 0 {
+		s = delta / max
+	}
+	if delta == 0 {
+		return 0, s, v
+	}
+	switch max {
+	case r:
+		h = (g - b) / delta
+	case g:
+		h = 2 + (b-r)/delta
+	default:
+		h = 4 + (r-g)/delta
+	}
+	h /= 6
+	if h < 0 {
+		h++
+	}
+	return h, s, v
+}
+
+func hsvToRGB(h, s, v float64) (r, g, b float64) {
+	h = (h - math.Floor(h)) * 6
+	i := math.Floor(h)
+	f := h - i
+	p := v * (1 - s)
+	q := v * (1 - s*f)
+	t := v * (1 - s*(1-f))
+	switch int(i) {
+	case 0:
+		return v, t, p
+	case 1:
+		return q, v, p
+	case 2:
+		return p, v, t
+	case 3:
+		return p, q, v
+	case 4:
+		return t, p, v
+	}
+	return v, p, q
+}
+
+// https://bottosson.github.io/posts/oklab/
+func linearToOKLab(r, g, b float64) (l, a, bb float64) {
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a = 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	bb = 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	return l, a, bb
+}
+
+func okLabToLinear(l, a, b float64) (r, g, bb float64) {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+
+	lc = lc * lc * lc
+	mc = mc * mc * mc
+	sc = sc * sc * sc
+
+	r = 4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc
+	g = -1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc
+	bb = -0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc
+	return r, g, bb
+}
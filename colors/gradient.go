@@ -0,0 +1,91 @@
+package colors
+
+import (
+	"sort"
+	"sync"
+)
+
+// Stop is a colour at a position along a gradient, positions run from 0 to 1
+type Stop struct {
+	Pos   float32 `json:"pos"`
+	Color Color   `json:"color"`
+}
+
+// Gradient blends between any number of stops in a colour space.
+// Two stops at the same position make a hard edge.
+type Gradient struct {
+	space Space
+	stops []Stop
+	vecs  []vec
+
+	lutOnce sync.Once
+	lut     []Color
+}
+
+// NewGradient returns a gradient through the stops, they don't need to be in order
+func NewGradient(space Space, stops ...Stop) *Gradient {
+	sorted := make([]Stop, len(stops))
+	copy(sorted, stops)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Pos < sorted[j].Pos })
+
+	vecs := make([]vec, len(sorted))
+	for i, stop := range sorted {
+		vecs[i] = space.from(stop.Color)
+	}
+	return &Gradient{space: space, stops: sorted, vecs: vecs}
+}
+
+// Space returns the colour space the gradient blends in
+func (g *Gradient) Space() Space {
+	return g.space
+}
+
+// Stops returns a copy of the gradient's stops in order
+func (g *Gradient) Stops() []Stop {
+	stops := make([]Stop, len(g.stops))
+	copy(stops, g.stops)
+	return stops
+}
+
+// At returns the colour at pct along the gradient
+func (g *Gradient) At(pct float32) Color {
+	n := len(g.stops)
+	if n == 0 {
+		return Color{}
+	}
+	if pct <= g.stops[0].Pos {
+		return g.stops[0].Color
+	}
+	if pct >= g.stops[n-1].Pos {
+		return g.stops[n-1].Color
+	}
+
+	i := sort.Search(n, func(i int) bool { return g.stops[i].Pos > pct })
+	start := g.stops[i-1].Pos
+	end := g.stops[i].Pos
+	t := float64((pct - start) / (end - start))
+	return g.space.to(g.space.mix(g.vecs[i-1], g.vecs[i], t))
+}
+
+// LUT returns 256 colours sampled evenly along the gradient.
+// It is worked out on first use and shared, so don't modify it.
+func (g *Gradient) LUT() []Color {
+	g.lutOnce.Do(func() {
+		g.lut = make([]Color, 256)
+		for i := range g.lut {
+			g.lut[i] = g.At(float32(i) / 255)
+		}
+	})
+	return g.lut
+}
+
+// GetGradient returns 256 colours blending from c1 to c2
+func GetGradient(c1, c2 Color) []Color {
+	return NewGradient(SRGB, Stop{0, c1}, Stop{1, c2}).LUT()
+}
+
+// GetDualGradient returns 256 colours blending from c1 to c2 in the first half
+// and from c3 to c4 in the second half
+func GetDualGradient(c1, c2, c3, c4 Color) []Color {
+	return NewGradient(SRGB, Stop{0, c1}, Stop{0.5, c2}, Stop{0.5, c3}, Stop{1, c4}).LUT()
+}
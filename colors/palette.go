@@ -0,0 +1,151 @@
+package colors
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// palette is the JSON form of a gradient
+//
+//	{"space": "oklab", "stops": [{"pos": 0, "color": "#0000af"}, {"pos": 1, "color": "#ffffff"}]}
+type palette struct {
+	Space Space  `json:"space"`
+	Stops []Stop `json:"stops"`
+}
+
+// MarshalJSON writes the gradient as a JSON palette
+func (g *Gradient) MarshalJSON() ([]byte, error) {
+	return json.Marshal(palette{g.space, g.stops})
+}
+
+// UnmarshalJSON reads the gradient from a JSON palette
+func (g *Gradient) UnmarshalJSON(data []byte) error {
+	var p palette
+	err := json.Unmarshal(data, &p)
+	if err != nil {
+		return err
+	}
+	if len(p.Stops) == 0 {
+		return fmt.Errorf("colors: palette has no stops")
+	}
+	parsed := NewGradient(p.Space, p.Stops...)
+	g.space = parsed.space
+	g.stops = parsed.stops
+	g.vecs = parsed.vecs
+	// the old stops' LUT no longer applies
+	g.lutOnce = sync.Once{}
+	g.lut = nil
+	return nil
+}
+
+// ParsePaletteJSON reads a gradient from a JSON palette
+func ParsePaletteJSON(data []byte) (*Gradient, error) {
+	var g Gradient
+	err := g.UnmarshalJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// ParsePalette reads a gradient from a text palette. Each line is a stop, a position
+// followed by a #rrggbb colour or r g b [a] values. A "space" line picks the colour space
+// and everything after a # at the start of a line is a comment.
+//
+//	space oklab
+//	0    #0000af
+//	0.5  80 160 244
+//	1    #ffffff
+func ParsePalette(r io.Reader) (*Gradient, error) {
+	space := SRGB
+	var stops []Stop
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if fields[0] == "space" {
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("colors: line %d: space needs one name", lineNum)
+			}
+			err := space.UnmarshalText([]byte(fields[1]))
+			if err != nil {
+				return nil, fmt.Errorf("colors: line %d: %v", lineNum, err)
+			}
+			continue
+		}
+
+		stop, err := parseStop(fields)
+		if err != nil {
+			return nil, fmt.Errorf("colors: line %d: %v", lineNum, err)
+		}
+		stops = append(stops, stop)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(stops) == 0 {
+		return nil, fmt.Errorf("colors: palette has no stops")
+	}
+	return NewGradient(space, stops...), nil
+}
+
+func parseStop(fields []string) (Stop, error) {
+	var stop Stop
+	pos, err := strconv.ParseFloat(fields[0], 32)
+	if err != nil {
+		return stop, err
+	}
+	stop.Pos = float32(pos)
+
+	if len(fields) == 2 {
+		stop.Color, err = ParseHex(fields[1])
+		return stop, err
+	}
+	if len(fields) != 4 && len(fields) != 5 {
+		return stop, fmt.Errorf("want a position and a colour, got %q", strings.Join(fields, " "))
+	}
+
+	channels := []byte{0, 0, 0, 255}
+	for i, field := range fields[1:] {
+		v, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return stop, err
+		}
+		channels[i] = byte(v)
+	}
+	stop.Color = Color{channels[0], channels[1], channels[2], channels[3]}
+	return stop, nil
+}
+
+// LoadPalette reads a gradient from a file, .json files are JSON palettes and
+// anything else is a text palette
+func LoadPalette(filename string) (*Gradient, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		return ParsePaletteJSON(data)
+	}
+	return ParsePalette(file)
+}
@@ -0,0 +1,80 @@
+package colors
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGradientJSONRoundTrip(t *testing.T) {
+	for _, space := range []Space{SRGB, LinearRGB, HSV, OKLab} {
+		g := NewGradient(space, Stop{0, RGB(0, 0, 175)}, Stop{0.5, Color{80, 160, 244, 128}}, Stop{1, RGB(255, 255, 255)})
+		data, err := json.Marshal(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded Gradient
+		err = json.Unmarshal(data, &decoded)
+		if err != nil {
+			t.Fatalf("%v: %v", space, err)
+		}
+		if decoded.Space() != space || !reflect.DeepEqual(decoded.Stops(), g.Stops()) {
+			t.Errorf("%v: decoded %v %v, want %v %v", space, decoded.Space(), decoded.Stops(), space, g.Stops())
+		}
+		if !reflect.DeepEqual(decoded.LUT(), g.LUT()) {
+			t.Errorf("%v: decoded gradient has different colours", space)
+		}
+	}
+}
+
+func TestGradientUnmarshalReplacesLUT(t *testing.T) {
+	g := NewGradient(SRGB, Stop{0, RGB(0, 0, 0)}, Stop{1, RGB(255, 255, 255)})
+	g.LUT()
+	err := json.Unmarshal([]byte(`{"space": "srgb", "stops": [{"pos": 0, "color": "#ff0000"}, {"pos": 1, "color": "#ff0000"}]}`), g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := g.LUT()[128]; c != RGB(255, 0, 0) {
+		t.Errorf("LUT after unmarshal has %v, want red", c)
+	}
+}
+
+func TestGradientUnmarshalErrors(t *testing.T) {
+	var g Gradient
+	if err := json.Unmarshal([]byte(`{"space": "srgb", "stops": []}`), &g); err == nil {
+		t.Error("no error for a palette without stops")
+	}
+	if err := json.Unmarshal([]byte(`{"space": "cmyk", "stops": [{"pos": 0, "color": "#000000"}]}`), &g); err == nil {
+		t.Error("no error for an unknown space")
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	text := "# ocean\nspace oklab\n0 #0000af\n0.5  80 160 244\n1 255 255 255 255\n"
+	g, err := ParsePalette(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Stop{{0, RGB(0, 0, 175)}, {0.5, RGB(80, 160, 244)}, {1, RGB(255, 255, 255)}}
+	if g.Space() != OKLab || !reflect.DeepEqual(g.Stops(), want) {
+		t.Errorf("parsed %v %v, want %v %v", g.Space(), g.Stops(), OKLab, want)
+	}
+}
+
+func TestHSVBlendWithGray(t *testing.T) {
+	// blue to white and gray to blue should stay blue all the way, not pass through red
+	blue := RGB(0, 0, 255)
+	for _, g := range []*Gradient{
+		NewGradient(HSV, Stop{0, blue}, Stop{1, RGB(255, 255, 255)}),
+		NewGradient(HSV, Stop{0, RGB(128, 128, 128)}, Stop{1, blue}),
+	} {
+		for i := 0; i <= 10; i++ {
+			c := g.At(float32(i) / 10)
+			if c.R != c.G || c.B < c.R {
+				t.Errorf("colour %v at %v isn't a shade of blue", c, float32(i)/10)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/sabith-th/games_with_go/colors"
 	. "github.com/sabith-th/games_with_go/evolvingpictures/apt"
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -34,22 +35,18 @@ func getMouseState() mouseState {
 	return result
 }
 
-type rgba struct {
-	r, g, b byte
-}
-
 func clear(pixels []byte) {
 	for i := range pixels {
 		pixels[i] = 0
 	}
 }
 
-func setPixel(x, y int, c rgba, pixels []byte) {
+func setPixel(x, y int, c colors.Color, pixels []byte) {
 	index := (y*winWidth + x) * 4
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
 }
 
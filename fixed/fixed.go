@@ -0,0 +1,196 @@
+package fixed
+
+import (
+	"math"
+	"strconv"
+)
+
+// Fixed is a Q16.16 fixed point number, 16 bits of whole number and 16 of fraction.
+// It covers -32768 to just under 32768 in steps of 1/65536. All the maths is done
+// with integers, so the same inputs give bit for bit the same results on every
+// machine. Results that don't fit wrap around like int32.
+type Fixed int32
+
+const fracBits = 16
+
+const (
+	// One is 1.0
+	One Fixed = 1 << fracBits
+	// Half is 0.5
+	Half Fixed = One / 2
+	// Pi is π rounded to the nearest step
+	Pi Fixed = 205887
+	// MaxValue is the largest Fixed
+	MaxValue Fixed = math.MaxInt32
+	// MinValue is the smallest Fixed
+	MinValue Fixed = math.MinInt32
+)
+
+// FromInt returns i as a Fixed
+func FromInt(i int) Fixed {
+	return Fixed(i << fracBits)
+}
+
+// FromFloat returns f rounded to the nearest Fixed. Only use it to bring values in
+// from outside the simulation, floats may round differently on different machines.
+func FromFloat(f float32) Fixed {
+	return Fixed(math.Round(float64(f) * float64(One)))
+}
+
+// FromFloat64 returns f rounded to the nearest Fixed
+func FromFloat64(f float64) Fixed {
+	return Fixed(math.Round(f * float64(One)))
+}
+
+// Float returns a as a float32, for drawing and other things outside the simulation
+func (a Fixed) Float() float32 {
+	return float32(a) / float32(One)
+}
+
+// Float64 returns a as a float64, it is exact
+func (a Fixed) Float64() float64 {
+	return float64(a) / float64(One)
+}
+
+// Int returns a rounded down to a whole number
+func (a Fixed) Int() int {
+	return int(a >> fracBits)
+}
+
+// String returns a as a decimal
+func (a Fixed) String() string {
+	return strconv.FormatFloat(a.Float64(), 'f', -1, 64)
+}
+
+// Mul returns a * b rounded to the nearest step
+func (a Fixed) Mul(b Fixed) Fixed {
+	return Fixed((int64(a)*int64(b) + 1<<(fracBits-1)) >> fracBits)
+}
+
+// Div returns a / b rounded towards zero, it panics if b is zero like integer division
+func (a Fixed) Div(b Fixed) Fixed {
+	return Fixed((int64(a) << fracBits) / int64(b))
+}
+
+// Abs returns the absolute value of a
+func (a Fixed) Abs() Fixed {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Floor returns a rounded down to a whole number
+func (a Fixed) Floor() Fixed {
+	return a &^ (One - 1)
+}
+
+// Ceil returns a rounded up to a whole number
+func (a Fixed) Ceil() Fixed {
+	return (a + One - 1).Floor()
+}
+
+// Frac returns the fractional part of a, a - a.Floor()
+func (a Fixed) Frac() Fixed {
+	return a & (One - 1)
+}
+
+// Sqrt returns the square root of a rounded down, or 0 if a is negative
+func (a Fixed) Sqrt() Fixed {
+	if a <= 0 {
+		return 0
+	}
+	// the square root of a Q32.32 number is Q16.16
+	return Fixed(isqrt(uint64(a) << fracBits))
+}
+
+// isqrt returns the square root of n rounded down, one bit at a time
+func isqrt(n uint64) uint64 {
+	var result uint64
+	bit := uint64(1) << 62
+	for bit > n {
+		bit >>= 2
+	}
+	for bit != 0 {
+		if n >= result+bit {
+			n -= result + bit
+			result = result>>1 + bit
+		} else {
+			result >>= 1
+		}
+		bit >>= 2
+	}
+	return result
+}
+
+// Atan2 returns the angle of the point x, y from the x axis in radians, from -π to π
+func Atan2(y, x Fixed) Fixed {
+	if x == 0 && y == 0 {
+		return 0
+	}
+	// in int64 so MinValue, which has no positive Fixed, still works
+	ax, ay := abs64(int64(x)), abs64(int64(y))
+
+	// work out the angle for the octant where 0 <= z <= 1 and mirror it round
+	var z Fixed
+	if ax >= ay {
+		z = Fixed((ay << fracBits) / ax)
+	} else {
+		z = Fixed((ax << fracBits) / ay)
+	}
+	angle := atan(z)
+	if ay > ax {
+		angle = Pi/2 - angle
+	}
+	if x < 0 {
+		angle = Pi - angle
+	}
+	if y < 0 {
+		angle = -angle
+	}
+	return angle
+}
+
+func abs64(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// atan approximates atan(z) for z from 0 to 1 with a polynomial, to within a few steps
+func atan(z Fixed) Fixed {
+	z2 := z.Mul(z)
+	p := Fixed(1365)             // 0.0208351
+	p = p.Mul(z2) - Fixed(5579)  // -0.0851330
+	p = p.Mul(z2) + Fixed(11806) // 0.1801410
+	p = p.Mul(z2) - Fixed(21646) // -0.3302995
+	p = p.Mul(z2) + Fixed(65527) // 0.9998660
+	return p.Mul(z)
+}
+
+// Min returns the smaller of a and b
+func (a Fixed) Min(b Fixed) Fixed {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of a and b
+func (a Fixed) Max(b Fixed) Fixed {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Clamp limits a to between lo and hi
+func (a Fixed) Clamp(lo, hi Fixed) Fixed {
+	return a.Max(lo).Min(hi)
+}
+
+// Lerp linearly interpolates from a to b
+func (a Fixed) Lerp(b, pct Fixed) Fixed {
+	return a + (b - a).Mul(pct)
+}
@@ -0,0 +1,175 @@
+package fixed
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// simulate runs a seeded mix of everything that has to be deterministic and
+// returns every intermediate result
+func simulate(seed int64) []Fixed {
+	rng := rand.New(rand.NewSource(seed))
+	random := func() Fixed { return Fixed(rng.Int31n(int32(FromInt(200)))) - FromInt(100) }
+
+	var results []Fixed
+	pos := Vector3{random(), random(), random()}
+	vel := Vector3{random(), random(), random()}
+	for i := 0; i < 1000; i++ {
+		a, b := random(), random()
+		if b == 0 {
+			b = One
+		}
+		results = append(results, a.Mul(b), a.Div(b), a.Abs().Sqrt(), Atan2(a, b))
+
+		dir := Normalize(Sub(Vector3{random(), random(), random()}, pos))
+		vel = Add(vel.Mult(FromFloat64(0.99)), dir.Mult(Half))
+		pos = Add(pos, vel.Mult(One/60))
+		results = append(results, pos.X, pos.Y, pos.Z, Length(vel))
+	}
+	return results
+}
+
+func TestDeterministic(t *testing.T) {
+	first, second := simulate(1), simulate(1)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("result %d is %d the first time and %d the second", i, first[i], second[i])
+		}
+	}
+}
+
+// simulateChecksum is the checksum of simulate(42) when the package was written
+const simulateChecksum = 909580552
+
+func TestGolden(t *testing.T) {
+	// these pin the exact bits, if they change saved replays and lockstep games break
+	tests := []struct {
+		name      string
+		got, want Fixed
+	}{
+		{"1.5 * 2.25", FromFloat64(1.5).Mul(FromFloat64(2.25)), 221184},
+		{"-1.5 * 2.25", FromFloat64(-1.5).Mul(FromFloat64(2.25)), -221184},
+		{"rounded mul", Fixed(3).Mul(Half), 2},
+		{"1 / 3", One.Div(FromInt(3)), 21845},
+		{"-1 / 3", (-One).Div(FromInt(3)), -21845},
+		{"sqrt 2", FromInt(2).Sqrt(), 92681},
+		{"sqrt 0.25", FromFloat64(0.25).Sqrt(), Half},
+		{"atan2 1, 1", Atan2(One, One), 51473},
+		{"atan2 1, -1", Atan2(One, -One), 154414},
+		{"atan2 -1, -1", Atan2(-One, -One), -154414},
+		{"atan2 1, 2", Atan2(One, FromInt(2)), 30386},
+		{"normalize 3, 4, 0 x", Normalize(Vector3{FromInt(3), FromInt(4), 0}).X, 39321},
+		{"normalize 3, 4, 0 y", Normalize(Vector3{FromInt(3), FromInt(4), 0}).Y, 52428},
+		{"normalize 1, 1, 1 z", Normalize(Vector3{One, One, One}).Z, 37837},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s is %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// and a checksum of the whole seeded run
+	var sum uint32
+	for _, r := range simulate(42) {
+		sum = sum*31 + uint32(r)
+	}
+	if sum != simulateChecksum {
+		t.Errorf("seeded run checksum is %d, want %d", sum, simulateChecksum)
+	}
+}
+
+func TestIsqrt(t *testing.T) {
+	tests := []struct {
+		n, want uint64
+	}{
+		{0, 0},
+		{1, 1},
+		{2, 1},
+		{3, 1},
+		{4, 2},
+		{99, 9},
+		{100, 10},
+		{1<<62 - 1, 1<<31 - 1},
+		{1 << 62, 1 << 31},
+		{math.MaxUint32 * math.MaxUint32, math.MaxUint32},
+		{math.MaxUint64, math.MaxUint32},
+	}
+	for _, tt := range tests {
+		if got := isqrt(tt.n); got != tt.want {
+			t.Errorf("isqrt(%d) is %d, want %d", tt.n, got, tt.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		n := rng.Uint64() >> uint(rng.Intn(64))
+		r := isqrt(n)
+		// r is the largest number whose square doesn't pass n
+		if r*r > n || (r+1)*(r+1) <= n && r+1 <= math.MaxUint32 {
+			t.Fatalf("isqrt(%d) is %d", n, r)
+		}
+	}
+}
+
+func TestSqrtEdges(t *testing.T) {
+	tests := []struct {
+		a, want Fixed
+	}{
+		{0, 0},
+		{-One, 0},
+		{MinValue, 0},
+		{1, 256},
+		{One, One},
+		{FromInt(4), FromInt(2)},
+		// sqrt(32768) is just over 181.02
+		{MaxValue, 11863283},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Sqrt(); got != tt.want {
+			t.Errorf("sqrt of %d is %d, want %d", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestAtan2Edges(t *testing.T) {
+	tests := []struct {
+		y, x, want Fixed
+	}{
+		{0, 0, 0},
+		{0, One, 0},
+		{0, MaxValue, 0},
+		{0, -One, Pi},
+		{0, MinValue, Pi},
+		{One, 0, Pi / 2},
+		{MaxValue, 0, Pi / 2},
+		{-One, 0, -Pi / 2},
+		{MinValue, 0, -Pi / 2},
+		{MaxValue, MaxValue, Atan2(One, One)},
+		{MinValue, MinValue, Atan2(-One, -One)},
+		{1, MaxValue, 0},
+	}
+	for _, tt := range tests {
+		if got := Atan2(tt.y, tt.x); got != tt.want {
+			t.Errorf("atan2(%d, %d) is %d, want %d", tt.y, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestAtan2Accuracy(t *testing.T) {
+	// a few steps of error is all the polynomial allows
+	const tolerance = 8.0 / float64(One)
+	for y := -50; y <= 50; y++ {
+		for x := -50; x <= 50; x++ {
+			fy, fx := FromFloat64(float64(y)*0.37), FromFloat64(float64(x)*0.41)
+			got := Atan2(fy, fx).Float64()
+			want := math.Atan2(fy.Float64(), fx.Float64())
+			if fx == 0 && fy == 0 {
+				want = 0
+			}
+			if math.Abs(got-want) > tolerance {
+				t.Fatalf("atan2(%v, %v) is %v, want %v", fy, fx, got, want)
+			}
+		}
+	}
+}
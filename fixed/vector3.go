@@ -0,0 +1,204 @@
+package fixed
+
+import "github.com/sabith-th/games_with_go/vector3"
+
+// Vector3 is the fixed point counterpart of vector3.Vector3, for simulations that
+// have to come out exactly the same everywhere, like lockstep netplay and replays
+type Vector3 struct {
+	X, Y, Z Fixed
+}
+
+// FromVector3 returns v rounded to the nearest fixed point vector
+func FromVector3(v vector3.Vector3) Vector3 {
+	return Vector3{FromFloat(v.X), FromFloat(v.Y), FromFloat(v.Z)}
+}
+
+// Vector3 returns a as a float vector, for drawing and other things outside the simulation
+func (a Vector3) Vector3() vector3.Vector3 {
+	return vector3.Vector3{X: a.X.Float(), Y: a.Y.Float(), Z: a.Z.Float()}
+}
+
+// Add adds two vectors and returns a new vector
+func Add(a, b Vector3) Vector3 {
+	return Vector3{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
+}
+
+// Sub subtracts b from a and returns a new vector
+func Sub(a, b Vector3) Vector3 {
+	return Vector3{a.X - b.X, a.Y - b.Y, a.Z - b.Z}
+}
+
+// Neg returns a vector pointing the opposite way
+func Neg(a Vector3) Vector3 {
+	return Vector3{-a.X, -a.Y, -a.Z}
+}
+
+// Mult multiplies a scalar to a vector and returns a new vector
+func Mult(a Vector3, b Fixed) Vector3 {
+	return Vector3{a.X.Mul(b), a.Y.Mul(b), a.Z.Mul(b)}
+}
+
+// Scale multiplies each component of a by the matching component of b
+func Scale(a, b Vector3) Vector3 {
+	return Vector3{a.X.Mul(b.X), a.Y.Mul(b.Y), a.Z.Mul(b.Z)}
+}
+
+// Dot returns the dot product of two vectors
+func Dot(a, b Vector3) Fixed {
+	// add up at full precision and round once
+	sum := int64(a.X)*int64(b.X) + int64(a.Y)*int64(b.Y) + int64(a.Z)*int64(b.Z)
+	return Fixed((sum + 1<<(fracBits-1)) >> fracBits)
+}
+
+// Cross returns the cross product of two vectors, it is perpendicular to both
+func Cross(a, b Vector3) Vector3 {
+	return Vector3{
+		a.Y.Mul(b.Z) - a.Z.Mul(b.Y),
+		a.Z.Mul(b.X) - a.X.Mul(b.Z),
+		a.X.Mul(b.Y) - a.Y.Mul(b.X),
+	}
+}
+
+// LengthSquared returns the squared magnitude of the given vector
+func LengthSquared(a Vector3) Fixed {
+	return Dot(a, a)
+}
+
+// Length returns the magnitude of the given vector. It is worked out at full
+// precision so it doesn't overflow even when LengthSquared would.
+func Length(a Vector3) Fixed {
+	x, y, z := int64(a.X), int64(a.Y), int64(a.Z)
+	// the sum of squares is Q32.32, its square root is Q16.16
+	return Fixed(isqrt(uint64(x*x) + uint64(y*y) + uint64(z*z)))
+}
+
+// Distance returns the distance between two vectors
+func Distance(a, b Vector3) Fixed {
+	return Length(Sub(a, b))
+}
+
+// DistanceSquared returns the squared distance between two vectors
+func DistanceSquared(a, b Vector3) Fixed {
+	return LengthSquared(Sub(a, b))
+}
+
+// Normalize returns a new vector with unit length and same direction as given vector.
+// A zero vector has no direction, so it is returned unchanged.
+func Normalize(a Vector3) Vector3 {
+	length := Length(a)
+	if length == 0 {
+		return Vector3{}
+	}
+	return Vector3{a.X.Div(length), a.Y.Div(length), a.Z.Div(length)}
+}
+
+// Lerp linearly interpolates between two vectors
+func Lerp(a, b Vector3, pct Fixed) Vector3 {
+	return Vector3{
+		a.X.Lerp(b.X, pct),
+		a.Y.Lerp(b.Y, pct),
+		a.Z.Lerp(b.Z, pct),
+	}
+}
+
+// Reflect bounces a off a surface with unit normal n
+func Reflect(a, n Vector3) Vector3 {
+	return Sub(a, Mult(n, 2*Dot(a, n)))
+}
+
+// Project returns the part of a that points along b, it is zero if b is zero
+func Project(a, b Vector3) Vector3 {
+	lengthSquared := LengthSquared(b)
+	if lengthSquared == 0 {
+		return Vector3{}
+	}
+	return Mult(b, Dot(a, b).Div(lengthSquared))
+}
+
+// Angle returns the angle between two vectors in radians, it is zero if either is zero
+func Angle(a, b Vector3) Fixed {
+	// atan2 of the sine and cosine stays accurate near 0 and π where acos doesn't
+	return Atan2(Length(Cross(a, b)), Dot(a, b))
+}
+
+// Min returns the smallest of each component of two vectors
+func Min(a, b Vector3) Vector3 {
+	return Vector3{a.X.Min(b.X), a.Y.Min(b.Y), a.Z.Min(b.Z)}
+}
+
+// Max returns the largest of each component of two vectors
+func Max(a, b Vector3) Vector3 {
+	return Vector3{a.X.Max(b.X), a.Y.Max(b.Y), a.Z.Max(b.Z)}
+}
+
+// Clamp limits each component of a to between the components of lo and hi
+func Clamp(a, lo, hi Vector3) Vector3 {
+	return Min(Max(a, lo), hi)
+}
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func ApproxEqual(a, b Vector3, epsilon Fixed) bool {
+	return (a.X-b.X).Abs() <= epsilon && (a.Y-b.Y).Abs() <= epsilon && (a.Z-b.Z).Abs() <= epsilon
+}
+
+// Methods
+
+// Add returns a + b
+func (a Vector3) Add(b Vector3) Vector3 { return Add(a, b) }
+
+// Sub returns a - b
+func (a Vector3) Sub(b Vector3) Vector3 { return Sub(a, b) }
+
+// Neg returns a vector pointing the opposite way
+func (a Vector3) Neg() Vector3 { return Neg(a) }
+
+// Mult returns a multiplied by the scalar b
+func (a Vector3) Mult(b Fixed) Vector3 { return Mult(a, b) }
+
+// Scale returns a multiplied component-wise by b
+func (a Vector3) Scale(b Vector3) Vector3 { return Scale(a, b) }
+
+// Dot returns the dot product of a and b
+func (a Vector3) Dot(b Vector3) Fixed { return Dot(a, b) }
+
+// Cross returns the cross product of a and b
+func (a Vector3) Cross(b Vector3) Vector3 { return Cross(a, b) }
+
+// LengthSquared returns the squared magnitude of the vector
+func (a Vector3) LengthSquared() Fixed { return LengthSquared(a) }
+
+// Length returns the magnitude of the vector
+func (a Vector3) Length() Fixed { return Length(a) }
+
+// Distance returns the distance from a to b
+func (a Vector3) Distance(b Vector3) Fixed { return Distance(a, b) }
+
+// DistanceSquared returns the squared distance from a to b
+func (a Vector3) DistanceSquared(b Vector3) Fixed { return DistanceSquared(a, b) }
+
+// Normalize returns the vector with unit length, a zero vector stays zero
+func (a Vector3) Normalize() Vector3 { return Normalize(a) }
+
+// Lerp linearly interpolates from a to b
+func (a Vector3) Lerp(b Vector3, pct Fixed) Vector3 { return Lerp(a, b, pct) }
+
+// Reflect bounces a off a surface with unit normal n
+func (a Vector3) Reflect(n Vector3) Vector3 { return Reflect(a, n) }
+
+// Project returns the part of a that points along b
+func (a Vector3) Project(b Vector3) Vector3 { return Project(a, b) }
+
+// Angle returns the angle between a and b in radians
+func (a Vector3) Angle(b Vector3) Fixed { return Angle(a, b) }
+
+// Min returns the smallest of each component of a and b
+func (a Vector3) Min(b Vector3) Vector3 { return Min(a, b) }
+
+// Max returns the largest of each component of a and b
+func (a Vector3) Max(b Vector3) Vector3 { return Max(a, b) }
+
+// Clamp limits each component of a to between the components of lo and hi
+func (a Vector3) Clamp(lo, hi Vector3) Vector3 { return Clamp(a, lo, hi) }
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func (a Vector3) ApproxEqual(b Vector3, epsilon Fixed) bool { return ApproxEqual(a, b, epsilon) }
@@ -0,0 +1,133 @@
+package font
+
+import (
+	"unicode"
+
+	"github.com/sabith-th/games_with_go/colors"
+)
+
+// GlyphWidth and GlyphHeight are the size of a glyph in font pixels
+const GlyphWidth, GlyphHeight = 3, 5
+
+var glyphs = map[rune][GlyphHeight]string{
+	'0': {"111", "101", "101", "101", "111"},
+	'1': {"110", "010", "010", "010", "111"},
+	'2': {"111", "001", "111", "100", "111"},
+	'3': {"111", "001", "011", "001", "111"},
+	'4': {"101", "101", "111", "001", "001"},
+	'5': {"111", "100", "111", "001", "111"},
+	'6': {"111", "100", "111", "101", "111"},
+	'7': {"111", "001", "010", "010", "010"},
+	'8': {"111", "101", "111", "101", "111"},
+	'9': {"111", "101", "111", "001", "111"},
+
+	'A': {"010", "101", "111", "101", "101"},
+	'B': {"110", "101", "110", "101", "110"},
+	'C': {"011", "100", "100", "100", "011"},
+	'D': {"110", "101", "101", "101", "110"},
+	'E': {"111", "100", "110", "100", "111"},
+	'F': {"111", "100", "110", "100", "100"},
+	'G': {"011", "100", "101", "101", "011"},
+	'H': {"101", "101", "111", "101", "101"},
+	'I': {"111", "010", "010", "010", "111"},
+	'J': {"001", "001", "001", "101", "010"},
+	'K': {"101", "101", "110", "101", "101"},
+	'L': {"100", "100", "100", "100", "111"},
+	'M': {"101", "111", "111", "101", "101"},
+	'N': {"110", "101", "101", "101", "101"},
+	'O': {"010", "101", "101", "101", "010"},
+	'P': {"110", "101", "110", "100", "100"},
+	'Q': {"010", "101", "101", "110", "011"},
+	'R': {"110", "101", "110", "101", "101"},
+	'S': {"011", "100", "010", "001", "110"},
+	'T': {"111", "010", "010", "010", "010"},
+	'U': {"101", "101", "101", "101", "111"},
+	'V': {"101", "101", "101", "101", "010"},
+	'W': {"101", "101", "111", "111", "101"},
+	'X': {"101", "101", "010", "101", "101"},
+	'Y': {"101", "101", "010", "010", "010"},
+	'Z': {"111", "001", "010", "100", "111"},
+
+	' ':  {"000", "000", "000", "000", "000"},
+	'.':  {"000", "000", "000", "000", "010"},
+	',':  {"000", "000", "000", "010", "100"},
+	':':  {"000", "010", "000", "010", "000"},
+	';':  {"000", "010", "000", "010", "100"},
+	'!':  {"010", "010", "010", "000", "010"},
+	'?':  {"111", "001", "010", "000", "010"},
+	'-':  {"000", "000", "111", "000", "000"},
+	'+':  {"000", "010", "111", "010", "000"},
+	'=':  {"000", "111", "000", "111", "000"},
+	'*':  {"000", "101", "010", "101", "000"},
+	'/':  {"001", "001", "010", "100", "100"},
+	'\\': {"100", "100", "010", "001", "001"},
+	'%':  {"101", "001", "010", "100", "101"},
+	'(':  {"001", "010", "010", "010", "001"},
+	')':  {"100", "010", "010", "010", "100"},
+	'[':  {"011", "010", "010", "010", "011"},
+	']':  {"110", "010", "010", "010", "110"},
+	'<':  {"001", "010", "100", "010", "001"},
+	'>':  {"100", "010", "001", "010", "100"},
+	'_':  {"000", "000", "000", "000", "111"},
+	'\'': {"010", "010", "000", "000", "000"},
+	'"':  {"101", "101", "000", "000", "000"},
+	'#':  {"101", "111", "101", "111", "101"},
+}
+
+// unknown is drawn for runes the font doesn't have, a filled box so it can't be
+// mistaken for a real character
+var unknown = [GlyphHeight]string{"111", "111", "111", "111", "111"}
+
+func glyph(r rune) [GlyphHeight]string {
+	if g, ok := glyphs[unicode.ToUpper(r)]; ok {
+		return g
+	}
+	return unknown
+}
+
+// Width returns the width in screen pixels of text drawn at size, glyphs are one font pixel apart
+func Width(text string, size int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (n*(GlyphWidth+1) - 1) * size
+}
+
+// Height returns the height in screen pixels of text drawn at size
+func Height(size int) int {
+	return GlyphHeight * size
+}
+
+// Draw draws text with its top left corner at x, y into a w by h rgba pixel buffer.
+// Each font pixel is a size by size square, lower case letters are drawn as upper case.
+func Draw(text string, x, y, size int, c colors.Color, pixels []byte, w, h int) {
+	for _, r := range text {
+		g := glyph(r)
+		for row, line := range g {
+			for col, bit := range line {
+				if bit == '1' {
+					fillRect(x+col*size, y+row*size, size, size, c, pixels, w, h)
+				}
+			}
+		}
+		x += (GlyphWidth + 1) * size
+	}
+}
+
+func fillRect(x, y, rw, rh int, c colors.Color, pixels []byte, w, h int) {
+	for py := y; py < y+rh; py++ {
+		if py < 0 || py >= h {
+			continue
+		}
+		for px := x; px < x+rw; px++ {
+			if px < 0 || px >= w {
+				continue
+			}
+			index := (py*w + px) * 4
+			pixels[index] = c.R
+			pixels[index+1] = c.G
+			pixels[index+2] = c.B
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/sabith-th/games_with_go/colors"
+)
+
+func TestUnknownGlyphIsDistinct(t *testing.T) {
+	for r, g := range glyphs {
+		if g == unknown {
+			t.Errorf("unknown runes look like %q", r)
+		}
+	}
+	if glyph('€') != unknown {
+		t.Error("a rune the font doesn't have isn't drawn as the unknown glyph")
+	}
+	if glyph('a') != glyphs['A'] {
+		t.Error("lower case isn't drawn as upper case")
+	}
+}
+
+func TestDrawSize(t *testing.T) {
+	const w, h = 40, 20
+	pixels := make([]byte, w*h*4)
+	white := colors.RGB(255, 255, 255)
+	Draw("18", 1, 2, 2, white, pixels, w, h)
+
+	// every lit pixel must be inside the box Width and Height describe
+	minX, minY, maxX, maxY := w, h, -1, -1
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if pixels[(y*w+x)*4] != 0 {
+				if x < minX {
+					minX = x
+				}
+				if y < minY {
+					minY = y
+				}
+				if x > maxX {
+					maxX = x
+				}
+				if y > maxY {
+					maxY = y
+				}
+			}
+		}
+	}
+	if minX != 1 || minY != 2 || maxX != 1+Width("18", 2)-1 || maxY != 2+Height(2)-1 {
+		t.Errorf("drew into %d,%d to %d,%d, want 1,2 to %d,%d", minX, minY, maxX, maxY, 1+Width("18", 2)-1, 2+Height(2)-1)
+	}
+
+	// drawing off the edges mustn't panic
+	Draw("HELLO", -5, -5, 4, white, pixels, w, h)
+	Draw("HELLO", 35, 15, 4, white, pixels, w, h)
+}
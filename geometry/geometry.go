@@ -0,0 +1,257 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Ray starts at Origin and goes on forever along Dir
+type Ray struct {
+	Origin vector3.Vector3
+	Dir    vector3.Vector3
+}
+
+// Sphere is every point within Radius of Center
+type Sphere struct {
+	Center vector3.Vector3
+	Radius float32
+}
+
+// AABB is an axis aligned box from Min to Max
+type AABB struct {
+	Min, Max vector3.Vector3
+}
+
+// Plane is every point p where Normal.Dot(p) == D, Normal should have unit length
+type Plane struct {
+	Normal vector3.Vector3
+	D      float32
+}
+
+// Segment is the straight line from A to B
+type Segment struct {
+	A, B vector3.Vector3
+}
+
+// NewPlane returns the plane through point facing along normal
+func NewPlane(normal, point vector3.Vector3) Plane {
+	n := normal.Normalize()
+	return Plane{n, n.Dot(point)}
+}
+
+// NewAABB returns the smallest box holding all the points
+func NewAABB(points ...vector3.Vector3) AABB {
+	if len(points) == 0 {
+		return AABB{}
+	}
+	box := AABB{points[0], points[0]}
+	for _, p := range points[1:] {
+		box.Min = box.Min.Min(p)
+		box.Max = box.Max.Max(p)
+	}
+	return box
+}
+
+// At returns the point t along the ray, t is in units of Dir
+func (r Ray) At(t float32) vector3.Vector3 {
+	return r.Origin.Add(r.Dir.Mult(t))
+}
+
+// ClosestPoint returns the point on the ray nearest to p
+func (r Ray) ClosestPoint(p vector3.Vector3) vector3.Vector3 {
+	lengthSquared := r.Dir.LengthSquared()
+	if lengthSquared == 0 {
+		return r.Origin
+	}
+	t := p.Sub(r.Origin).Dot(r.Dir) / lengthSquared
+	if t < 0 {
+		t = 0
+	}
+	return r.At(t)
+}
+
+// IntersectSphere returns where the ray first hits s. A ray starting inside s hits at 0.
+func (r Ray) IntersectSphere(s Sphere) (t float32, hit bool) {
+	// solve |origin + t*dir - center|^2 = radius^2 for t
+	m := r.Origin.Sub(s.Center)
+	a := r.Dir.LengthSquared()
+	b := m.Dot(r.Dir)
+	c := m.LengthSquared() - s.Radius*s.Radius
+	if c <= 0 {
+		return 0, true
+	}
+	if b > 0 || a == 0 {
+		// starting outside and pointing away
+		return 0, false
+	}
+	discriminant := b*b - a*c
+	if discriminant < 0 {
+		return 0, false
+	}
+	return (-b - float32(math.Sqrt(float64(discriminant)))) / a, true
+}
+
+// IntersectAABB returns where the ray first hits box. A ray starting inside box hits at 0.
+func (r Ray) IntersectAABB(box AABB) (t float32, hit bool) {
+	tMin := float32(0)
+	tMax := float32(math.MaxFloat32)
+
+	origin := [3]float32{r.Origin.X, r.Origin.Y, r.Origin.Z}
+	dir := [3]float32{r.Dir.X, r.Dir.Y, r.Dir.Z}
+	min := [3]float32{box.Min.X, box.Min.Y, box.Min.Z}
+	max := [3]float32{box.Max.X, box.Max.Y, box.Max.Z}
+
+	// clip the ray against the pair of planes on each axis
+	for i := 0; i < 3; i++ {
+		if dir[i] == 0 {
+			if origin[i] < min[i] || origin[i] > max[i] {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (min[i] - origin[i]) / dir[i]
+		t2 := (max[i] - origin[i]) / dir[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tMin {
+			tMin = t1
+		}
+		if t2 < tMax {
+			tMax = t2
+		}
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// IntersectPlane returns where the ray crosses p, it misses if it is parallel or pointing away
+func (r Ray) IntersectPlane(p Plane) (t float32, hit bool) {
+	denom := p.Normal.Dot(r.Dir)
+	if denom == 0 {
+		return 0, false
+	}
+	t = (p.D - p.Normal.Dot(r.Origin)) / denom
+	if t < 0 {
+		return 0, false
+	}
+	return t, true
+}
+
+// Contains reports whether p is inside s
+func (s Sphere) Contains(p vector3.Vector3) bool {
+	return vector3.DistanceSquared(s.Center, p) <= s.Radius*s.Radius
+}
+
+// ClosestPoint returns the point in s nearest to p, p itself if it is inside
+func (s Sphere) ClosestPoint(p vector3.Vector3) vector3.Vector3 {
+	if s.Contains(p) {
+		return p
+	}
+	return s.Center.Add(p.Sub(s.Center).Normalize().Mult(s.Radius))
+}
+
+// Overlaps reports whether two spheres touch
+func (s Sphere) Overlaps(o Sphere) bool {
+	radii := s.Radius + o.Radius
+	return vector3.DistanceSquared(s.Center, o.Center) <= radii*radii
+}
+
+// OverlapsAABB reports whether s touches box
+func (s Sphere) OverlapsAABB(box AABB) bool {
+	return vector3.DistanceSquared(box.ClosestPoint(s.Center), s.Center) <= s.Radius*s.Radius
+}
+
+// Bounds returns the smallest box holding s
+func (s Sphere) Bounds() AABB {
+	r := vector3.Vector3{X: s.Radius, Y: s.Radius, Z: s.Radius}
+	return AABB{s.Center.Sub(r), s.Center.Add(r)}
+}
+
+// Center returns the middle of box
+func (box AABB) Center() vector3.Vector3 {
+	return vector3.Lerp(box.Min, box.Max, 0.5)
+}
+
+// Size returns the width, height and depth of box
+func (box AABB) Size() vector3.Vector3 {
+	return box.Max.Sub(box.Min)
+}
+
+// Contains reports whether p is inside box
+func (box AABB) Contains(p vector3.Vector3) bool {
+	return p.X >= box.Min.X && p.X <= box.Max.X &&
+		p.Y >= box.Min.Y && p.Y <= box.Max.Y &&
+		p.Z >= box.Min.Z && p.Z <= box.Max.Z
+}
+
+// ClosestPoint returns the point in box nearest to p, p itself if it is inside
+func (box AABB) ClosestPoint(p vector3.Vector3) vector3.Vector3 {
+	return p.Clamp(box.Min, box.Max)
+}
+
+// Overlaps reports whether two boxes touch
+func (box AABB) Overlaps(o AABB) bool {
+	return box.Min.X <= o.Max.X && box.Max.X >= o.Min.X &&
+		box.Min.Y <= o.Max.Y && box.Max.Y >= o.Min.Y &&
+		box.Min.Z <= o.Max.Z && box.Max.Z >= o.Min.Z
+}
+
+// Union returns the smallest box holding both boxes
+func (box AABB) Union(o AABB) AABB {
+	return AABB{box.Min.Min(o.Min), box.Max.Max(o.Max)}
+}
+
+// Distance returns how far p is in front of the plane, it is negative behind it
+func (p Plane) Distance(point vector3.Vector3) float32 {
+	return p.Normal.Dot(point) - p.D
+}
+
+// ClosestPoint returns the point on the plane nearest to point
+func (p Plane) ClosestPoint(point vector3.Vector3) vector3.Vector3 {
+	return point.Sub(p.Normal.Mult(p.Distance(point)))
+}
+
+// OverlapsSphere reports whether s touches the plane
+func (p Plane) OverlapsSphere(s Sphere) bool {
+	d := p.Distance(s.Center)
+	return d <= s.Radius && d >= -s.Radius
+}
+
+// Length returns the length of the segment
+func (s Segment) Length() float32 {
+	return vector3.Distance(s.A, s.B)
+}
+
+// ClosestPoint returns the point on the segment nearest to p
+func (s Segment) ClosestPoint(p vector3.Vector3) vector3.Vector3 {
+	ab := s.B.Sub(s.A)
+	lengthSquared := ab.LengthSquared()
+	if lengthSquared == 0 {
+		return s.A
+	}
+	t := p.Sub(s.A).Dot(ab) / lengthSquared
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return s.A.Add(ab.Mult(t))
+}
+
+// OverlapsSphere reports whether the segment passes through s
+func (s Segment) OverlapsSphere(sphere Sphere) bool {
+	return sphere.Contains(s.ClosestPoint(sphere.Center))
+}
+
+// IntersectAABB returns how far along the segment, from 0 at A to 1 at B, it first enters box
+func (s Segment) IntersectAABB(box AABB) (t float32, hit bool) {
+	t, hit = Ray{s.A, s.B.Sub(s.A)}.IntersectAABB(box)
+	if !hit || t > 1 {
+		return 0, false
+	}
+	return t, true
+}
@@ -0,0 +1,174 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) <= 1e-5
+}
+
+func nearVec(a, b vector3.Vector3) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z)
+}
+
+func v(x, y, z float32) vector3.Vector3 {
+	return vector3.Vector3{X: x, Y: y, Z: z}
+}
+
+func TestRayIntersectSphere(t *testing.T) {
+	sphere := Sphere{Center: v(0, 0, 10), Radius: 2}
+	tests := []struct {
+		name string
+		ray  Ray
+		t    float32
+		hit  bool
+	}{
+		{"straight on", Ray{v(0, 0, 0), v(0, 0, 1)}, 8, true},
+		{"long dir", Ray{v(0, 0, 0), v(0, 0, 2)}, 4, true},
+		{"grazing", Ray{v(2, 0, 0), v(0, 0, 1)}, 10, true},
+		{"origin inside", Ray{v(0, 1, 10), v(1, 0, 0)}, 0, true},
+		{"sphere behind", Ray{v(0, 0, 20), v(0, 0, 1)}, 0, false},
+		{"miss", Ray{v(3, 0, 0), v(0, 0, 1)}, 0, false},
+		{"zero dir outside", Ray{v(0, 0, 0), v(0, 0, 0)}, 0, false},
+	}
+	for _, tt := range tests {
+		got, hit := tt.ray.IntersectSphere(sphere)
+		if hit != tt.hit || !near(got, tt.t) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, hit, tt.t, tt.hit)
+		}
+	}
+}
+
+func TestRayIntersectAABB(t *testing.T) {
+	box := AABB{v(-1, -1, 4), v(1, 1, 6)}
+	tests := []struct {
+		name string
+		ray  Ray
+		t    float32
+		hit  bool
+	}{
+		{"straight on", Ray{v(0, 0, 0), v(0, 0, 1)}, 4, true},
+		{"diagonal", Ray{v(-5, 0, 0), v(1, 0, 1)}, 4, true},
+		{"parallel inside the slabs", Ray{v(0.5, -0.5, 0), v(0, 0, 1)}, 4, true},
+		{"parallel on the edge", Ray{v(1, 1, 0), v(0, 0, 1)}, 4, true},
+		{"parallel outside the x slab", Ray{v(2, 0, 0), v(0, 0, 1)}, 0, false},
+		{"parallel outside the y slab", Ray{v(0, -1.5, 0), v(0, 0, 1)}, 0, false},
+		{"origin inside", Ray{v(0, 0, 5), v(0, 1, 0)}, 0, true},
+		{"box behind", Ray{v(0, 0, 10), v(0, 0, 1)}, 0, false},
+		{"miss", Ray{v(0, 0, 0), v(1, 0, 1)}, 0, false},
+	}
+	for _, tt := range tests {
+		got, hit := tt.ray.IntersectAABB(box)
+		if hit != tt.hit || !near(got, tt.t) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, hit, tt.t, tt.hit)
+		}
+	}
+}
+
+func TestRayIntersectPlane(t *testing.T) {
+	plane := NewPlane(v(0, 1, 0), v(0, 3, 0))
+	tests := []struct {
+		name string
+		ray  Ray
+		t    float32
+		hit  bool
+	}{
+		{"from below", Ray{v(5, 0, 5), v(0, 1, 0)}, 3, true},
+		{"from above", Ray{v(0, 5, 0), v(0, -2, 0)}, 1, true},
+		{"slanted", Ray{v(0, 0, 0), v(1, 1, 0)}, 3, true},
+		{"parallel", Ray{v(0, 0, 0), v(1, 0, 0)}, 0, false},
+		{"parallel in the plane", Ray{v(0, 3, 0), v(1, 0, 0)}, 0, false},
+		{"plane behind", Ray{v(0, 5, 0), v(0, 1, 0)}, 0, false},
+	}
+	for _, tt := range tests {
+		got, hit := tt.ray.IntersectPlane(plane)
+		if hit != tt.hit || !near(got, tt.t) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, hit, tt.t, tt.hit)
+		}
+	}
+}
+
+func TestSegmentIntersectAABB(t *testing.T) {
+	box := AABB{v(-1, -1, 4), v(1, 1, 6)}
+	tests := []struct {
+		name    string
+		segment Segment
+		t       float32
+		hit     bool
+	}{
+		{"through", Segment{v(0, 0, 0), v(0, 0, 10)}, 0.4, true},
+		{"ends inside", Segment{v(0, 0, 0), v(0, 0, 5)}, 0.8, true},
+		{"starts inside", Segment{v(0, 0, 5), v(0, 0, 10)}, 0, true},
+		{"stops short", Segment{v(0, 0, 0), v(0, 0, 3)}, 0, false},
+		{"pointing away", Segment{v(0, 0, 10), v(0, 0, 20)}, 0, false},
+		{"passes by", Segment{v(2, 0, 0), v(2, 0, 10)}, 0, false},
+	}
+	for _, tt := range tests {
+		got, hit := tt.segment.IntersectAABB(box)
+		if hit != tt.hit || !near(got, tt.t) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, hit, tt.t, tt.hit)
+		}
+	}
+}
+
+func TestClosestPoints(t *testing.T) {
+	ray := Ray{v(0, 0, 0), v(2, 0, 0)}
+	sphere := Sphere{Center: v(0, 0, 0), Radius: 2}
+	box := AABB{v(-1, -1, -1), v(1, 1, 1)}
+	plane := NewPlane(v(0, 0, 1), v(0, 0, 2))
+	segment := Segment{v(0, 0, 0), v(4, 0, 0)}
+
+	tests := []struct {
+		name      string
+		got, want vector3.Vector3
+	}{
+		{"ray beside", ray.ClosestPoint(v(3, 5, 0)), v(3, 0, 0)},
+		{"ray behind", ray.ClosestPoint(v(-3, 1, 0)), v(0, 0, 0)},
+		{"ray with no dir", Ray{v(1, 2, 3), vector3.Vector3{}}.ClosestPoint(v(5, 5, 5)), v(1, 2, 3)},
+		{"sphere outside", sphere.ClosestPoint(v(0, 10, 0)), v(0, 2, 0)},
+		{"sphere inside", sphere.ClosestPoint(v(0.5, 0.5, 0)), v(0.5, 0.5, 0)},
+		{"box face", box.ClosestPoint(v(5, 0.5, 0)), v(1, 0.5, 0)},
+		{"box corner", box.ClosestPoint(v(5, -5, 5)), v(1, -1, 1)},
+		{"box inside", box.ClosestPoint(v(0.5, 0, -0.5)), v(0.5, 0, -0.5)},
+		{"plane in front", plane.ClosestPoint(v(1, 2, 7)), v(1, 2, 2)},
+		{"plane behind", plane.ClosestPoint(v(1, 2, -3)), v(1, 2, 2)},
+		{"segment middle", segment.ClosestPoint(v(1, 3, 0)), v(1, 0, 0)},
+		{"segment before A", segment.ClosestPoint(v(-2, 1, 0)), v(0, 0, 0)},
+		{"segment past B", segment.ClosestPoint(v(9, 1, 0)), v(4, 0, 0)},
+		{"segment with no length", Segment{v(1, 1, 1), v(1, 1, 1)}.ClosestPoint(v(5, 5, 5)), v(1, 1, 1)},
+	}
+	for _, tt := range tests {
+		if !nearVec(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSphereOverlapsAABB(t *testing.T) {
+	box := AABB{v(0, 0, 0), v(2, 2, 2)}
+	tests := []struct {
+		name   string
+		sphere Sphere
+		want   bool
+	}{
+		{"touching a face", Sphere{v(3, 1, 1), 1}, true},
+		{"just off a face", Sphere{v(3.01, 1, 1), 1}, false},
+		{"inside", Sphere{v(1, 1, 1), 0.1}, true},
+		{"around the box", Sphere{v(1, 1, 1), 10}, true},
+		// inside the corner's bounding box but further than the radius from the corner
+		{"diagonal corner near miss", Sphere{v(2.6, 2.6, 2.6), 1}, false},
+		{"diagonal corner hit", Sphere{v(2.5, 2.5, 2.5), 1}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.sphere.OverlapsAABB(box); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if !tt.sphere.Bounds().Overlaps(box) && tt.want {
+			t.Errorf("%s: overlaps but its bounds don't", tt.name)
+		}
+	}
+}
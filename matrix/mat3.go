@@ -0,0 +1,135 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Mat3 is a 3x3 matrix stored column by column, element (row, col) is at col*3+row.
+// Vectors are columns, so m.Mul(n) applies n first and then m.
+type Mat3 [9]float32
+
+// Identity3 returns the 3x3 identity matrix
+func Identity3() Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// rows3 builds a matrix from its elements written row by row, which is easier to read
+func rows3(m00, m01, m02, m10, m11, m12, m20, m21, m22 float32) Mat3 {
+	return Mat3{
+		m00, m10, m20,
+		m01, m11, m21,
+		m02, m12, m22,
+	}
+}
+
+// At returns the element at row, col
+func (m Mat3) At(row, col int) float32 {
+	return m[col*3+row]
+}
+
+// Mul returns m * n
+func (m Mat3) Mul(n Mat3) Mat3 {
+	var result Mat3
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			var sum float32
+			for k := 0; k < 3; k++ {
+				sum += m[k*3+row] * n[col*3+k]
+			}
+			result[col*3+row] = sum
+		}
+	}
+	return result
+}
+
+// MulVector3 returns m * v
+func (m Mat3) MulVector3(v vector3.Vector3) vector3.Vector3 {
+	return vector3.Vector3{
+		X: m[0]*v.X + m[3]*v.Y + m[6]*v.Z,
+		Y: m[1]*v.X + m[4]*v.Y + m[7]*v.Z,
+		Z: m[2]*v.X + m[5]*v.Y + m[8]*v.Z,
+	}
+}
+
+// Transpose returns m flipped along its diagonal
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{
+		m[0], m[3], m[6],
+		m[1], m[4], m[7],
+		m[2], m[5], m[8],
+	}
+}
+
+// Determinant returns the determinant of m
+func (m Mat3) Determinant() float32 {
+	return m[0]*(m[4]*m[8]-m[7]*m[5]) -
+		m[3]*(m[1]*m[8]-m[7]*m[2]) +
+		m[6]*(m[1]*m[5]-m[4]*m[2])
+}
+
+// Inverse returns the inverse of m, ok is false if m can't be inverted
+func (m Mat3) Inverse() (inv Mat3, ok bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	invDet := 1 / det
+	inv = rows3(
+		(m[4]*m[8]-m[7]*m[5])*invDet, (m[6]*m[5]-m[3]*m[8])*invDet, (m[3]*m[7]-m[6]*m[4])*invDet,
+		(m[7]*m[2]-m[1]*m[8])*invDet, (m[0]*m[8]-m[6]*m[2])*invDet, (m[6]*m[1]-m[0]*m[7])*invDet,
+		(m[1]*m[5]-m[4]*m[2])*invDet, (m[3]*m[2]-m[0]*m[5])*invDet, (m[0]*m[4]-m[3]*m[1])*invDet,
+	)
+	return inv, true
+}
+
+// Mat4 returns m in the top left of a 4x4 identity matrix
+func (m Mat3) Mat4() Mat4 {
+	return Mat4{
+		m[0], m[1], m[2], 0,
+		m[3], m[4], m[5], 0,
+		m[6], m[7], m[8], 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Translate3 returns a 2d translation in homogeneous coordinates, points need a z of 1
+func Translate3(x, y float32) Mat3 {
+	return rows3(
+		1, 0, x,
+		0, 1, y,
+		0, 0, 1,
+	)
+}
+
+// Scale3 returns a matrix scaling each axis by the matching component of s
+func Scale3(s vector3.Vector3) Mat3 {
+	return rows3(
+		s.X, 0, 0,
+		0, s.Y, 0,
+		0, 0, s.Z,
+	)
+}
+
+// Rotate3 returns a rotation of angle radians anticlockwise around axis.
+// Rotating around the z axis is also a 2d rotation in homogeneous coordinates.
+// A zero axis has no direction to turn around, so it gives the identity.
+func Rotate3(axis vector3.Vector3, angle float32) Mat3 {
+	if axis == (vector3.Vector3{}) {
+		return Identity3()
+	}
+	a := axis.Normalize()
+	sin, cos := math.Sincos(float64(angle))
+	s, c := float32(sin), float32(cos)
+	t := 1 - c
+	return rows3(
+		t*a.X*a.X+c, t*a.X*a.Y-s*a.Z, t*a.X*a.Z+s*a.Y,
+		t*a.X*a.Y+s*a.Z, t*a.Y*a.Y+c, t*a.Y*a.Z-s*a.X,
+		t*a.X*a.Z-s*a.Y, t*a.Y*a.Z+s*a.X, t*a.Z*a.Z+c,
+	)
+}
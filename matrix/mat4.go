@@ -0,0 +1,179 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/vector3"
+	"github.com/sabith-th/games_with_go/vector4"
+)
+
+// Mat4 is a 4x4 matrix stored column by column, element (row, col) is at col*4+row.
+// This is the layout OpenGL expects. Vectors are columns, so m.Mul(n) applies n first.
+type Mat4 [16]float32
+
+// Identity4 returns the 4x4 identity matrix
+func Identity4() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// At returns the element at row, col
+func (m Mat4) At(row, col int) float32 {
+	return m[col*4+row]
+}
+
+// Mul returns m * n
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var result Mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += m[k*4+row] * n[col*4+k]
+			}
+			result[col*4+row] = sum
+		}
+	}
+	return result
+}
+
+// MulVector4 returns m * v
+func (m Mat4) MulVector4(v vector4.Vector4) vector4.Vector4 {
+	return vector4.Vector4{
+		X: m[0]*v.X + m[4]*v.Y + m[8]*v.Z + m[12]*v.W,
+		Y: m[1]*v.X + m[5]*v.Y + m[9]*v.Z + m[13]*v.W,
+		Z: m[2]*v.X + m[6]*v.Y + m[10]*v.Z + m[14]*v.W,
+		W: m[3]*v.X + m[7]*v.Y + m[11]*v.Z + m[15]*v.W,
+	}
+}
+
+// MulPoint transforms the point p, including translation and the perspective divide
+func (m Mat4) MulPoint(p vector3.Vector3) vector3.Vector3 {
+	return vector4.PerspectiveDivide(m.MulVector4(vector4.FromVector3(p, 1)))
+}
+
+// MulDirection transforms the direction d, which ignores translation
+func (m Mat4) MulDirection(d vector3.Vector3) vector3.Vector3 {
+	return m.MulVector4(vector4.FromVector3(d, 0)).Vector3()
+}
+
+// Transpose returns m flipped along its diagonal
+func (m Mat4) Transpose() Mat4 {
+	var result Mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			result[row*4+col] = m[col*4+row]
+		}
+	}
+	return result
+}
+
+// Mat3 returns the top left 3x3 of m, the rotation and scale without translation
+func (m Mat4) Mat3() Mat3 {
+	return Mat3{
+		m[0], m[1], m[2],
+		m[4], m[5], m[6],
+		m[8], m[9], m[10],
+	}
+}
+
+// Inverse returns the inverse of m, ok is false if m can't be inverted
+func (m Mat4) Inverse() (inv Mat4, ok bool) {
+	// cofactors, worked out in float64 to keep precision
+	var a [16]float64
+	for i, v := range m {
+		a[i] = float64(v)
+	}
+	var c [16]float64
+	c[0] = a[5]*a[10]*a[15] - a[5]*a[11]*a[14] - a[9]*a[6]*a[15] + a[9]*a[7]*a[14] + a[13]*a[6]*a[11] - a[13]*a[7]*a[10]
+	c[4] = -a[4]*a[10]*a[15] + a[4]*a[11]*a[14] + a[8]*a[6]*a[15] - a[8]*a[7]*a[14] - a[12]*a[6]*a[11] + a[12]*a[7]*a[10]
+	c[8] = a[4]*a[9]*a[15] - a[4]*a[11]*a[13] - a[8]*a[5]*a[15] + a[8]*a[7]*a[13] + a[12]*a[5]*a[11] - a[12]*a[7]*a[9]
+	c[12] = -a[4]*a[9]*a[14] + a[4]*a[10]*a[13] + a[8]*a[5]*a[14] - a[8]*a[6]*a[13] - a[12]*a[5]*a[10] + a[12]*a[6]*a[9]
+	c[1] = -a[1]*a[10]*a[15] + a[1]*a[11]*a[14] + a[9]*a[2]*a[15] - a[9]*a[3]*a[14] - a[13]*a[2]*a[11] + a[13]*a[3]*a[10]
+	c[5] = a[0]*a[10]*a[15] - a[0]*a[11]*a[14] - a[8]*a[2]*a[15] + a[8]*a[3]*a[14] + a[12]*a[2]*a[11] - a[12]*a[3]*a[10]
+	c[9] = -a[0]*a[9]*a[15] + a[0]*a[11]*a[13] + a[8]*a[1]*a[15] - a[8]*a[3]*a[13] - a[12]*a[1]*a[11] + a[12]*a[3]*a[9]
+	c[13] = a[0]*a[9]*a[14] - a[0]*a[10]*a[13] - a[8]*a[1]*a[14] + a[8]*a[2]*a[13] + a[12]*a[1]*a[10] - a[12]*a[2]*a[9]
+	c[2] = a[1]*a[6]*a[15] - a[1]*a[7]*a[14] - a[5]*a[2]*a[15] + a[5]*a[3]*a[14] + a[13]*a[2]*a[7] - a[13]*a[3]*a[6]
+	c[6] = -a[0]*a[6]*a[15] + a[0]*a[7]*a[14] + a[4]*a[2]*a[15] - a[4]*a[3]*a[14] - a[12]*a[2]*a[7] + a[12]*a[3]*a[6]
+	c[10] = a[0]*a[5]*a[15] - a[0]*a[7]*a[13] - a[4]*a[1]*a[15] + a[4]*a[3]*a[13] + a[12]*a[1]*a[7] - a[12]*a[3]*a[5]
+	c[14] = -a[0]*a[5]*a[14] + a[0]*a[6]*a[13] + a[4]*a[1]*a[14] - a[4]*a[2]*a[13] - a[12]*a[1]*a[6] + a[12]*a[2]*a[5]
+	c[3] = -a[1]*a[6]*a[11] + a[1]*a[7]*a[10] + a[5]*a[2]*a[11] - a[5]*a[3]*a[10] - a[9]*a[2]*a[7] + a[9]*a[3]*a[6]
+	c[7] = a[0]*a[6]*a[11] - a[0]*a[7]*a[10] - a[4]*a[2]*a[11] + a[4]*a[3]*a[10] + a[8]*a[2]*a[7] - a[8]*a[3]*a[6]
+	c[11] = -a[0]*a[5]*a[11] + a[0]*a[7]*a[9] + a[4]*a[1]*a[11] - a[4]*a[3]*a[9] - a[8]*a[1]*a[7] + a[8]*a[3]*a[5]
+	c[15] = a[0]*a[5]*a[10] - a[0]*a[6]*a[9] - a[4]*a[1]*a[10] + a[4]*a[2]*a[9] + a[8]*a[1]*a[6] - a[8]*a[2]*a[5]
+
+	det := a[0]*c[0] + a[1]*c[4] + a[2]*c[8] + a[3]*c[12]
+	if det == 0 {
+		return Mat4{}, false
+	}
+	for i := range inv {
+		inv[i] = float32(c[i] / det)
+	}
+	return inv, true
+}
+
+// Translate4 returns a matrix moving points by t
+func Translate4(t vector3.Vector3) Mat4 {
+	m := Identity4()
+	m[12] = t.X
+	m[13] = t.Y
+	m[14] = t.Z
+	return m
+}
+
+// Scale4 returns a matrix scaling each axis by the matching component of s
+func Scale4(s vector3.Vector3) Mat4 {
+	return Scale3(s).Mat4()
+}
+
+// Rotate4 returns a rotation of angle radians anticlockwise around axis
+func Rotate4(axis vector3.Vector3, angle float32) Mat4 {
+	return Rotate3(axis, angle).Mat4()
+}
+
+// LookAt returns a view matrix for a camera at eye looking towards center. The camera
+// looks down its own -z axis with up roughly along +y, like gluLookAt.
+func LookAt(eye, center, up vector3.Vector3) Mat4 {
+	f := center.Sub(eye).Normalize()
+	s := f.Cross(up).Normalize()
+	u := s.Cross(f)
+
+	m := Identity4()
+	m[0], m[4], m[8] = s.X, s.Y, s.Z
+	m[1], m[5], m[9] = u.X, u.Y, u.Z
+	m[2], m[6], m[10] = -f.X, -f.Y, -f.Z
+	m[12] = -s.Dot(eye)
+	m[13] = -u.Dot(eye)
+	m[14] = f.Dot(eye)
+	return m
+}
+
+// Perspective returns a projection with a vertical field of view of fovY radians.
+// Points between the near and far planes end up with z from -1 to 1, like gluPerspective.
+func Perspective(fovY, aspect, near, far float32) Mat4 {
+	f := float32(1 / math.Tan(float64(fovY)/2))
+	var m Mat4
+	m[0] = f / aspect
+	m[5] = f
+	m[10] = (far + near) / (near - far)
+	m[11] = -1
+	m[14] = 2 * far * near / (near - far)
+	return m
+}
+
+// Ortho returns a parallel projection of the box between the six planes onto -1 to 1
+// on every axis, like glOrtho
+func Ortho(left, right, bottom, top, near, far float32) Mat4 {
+	m := Identity4()
+	m[0] = 2 / (right - left)
+	m[5] = 2 / (top - bottom)
+	m[10] = -2 / (far - near)
+	m[12] = -(right + left) / (right - left)
+	m[13] = -(top + bottom) / (top - bottom)
+	m[14] = -(far + near) / (far - near)
+	return m
+}
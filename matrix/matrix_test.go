@@ -0,0 +1,204 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/vector3"
+	"github.com/sabith-th/games_with_go/vector4"
+)
+
+const epsilon = 1e-4
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) <= epsilon
+}
+
+func nearVec(a, b vector3.Vector3) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z)
+}
+
+// sameRotation compares quaternions allowing for q and -q being the same rotation
+func sameRotation(a, b Quaternion) bool {
+	return near(float32(math.Abs(float64(a.Dot(b)))), 1)
+}
+
+func randomVector(r *rand.Rand) vector3.Vector3 {
+	return vector3.Vector3{X: r.Float32()*2 - 1, Y: r.Float32()*2 - 1, Z: r.Float32()*2 - 1}
+}
+
+func randomRotation(r *rand.Rand) Quaternion {
+	return FromAxisAngle(randomVector(r), r.Float32()*2*math.Pi)
+}
+
+func TestMat3Inverse(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		m := Rotate3(randomVector(r), r.Float32()*6).Mul(Scale3(vector3.Vector3{X: 0.5 + r.Float32(), Y: 0.5 + r.Float32(), Z: 0.5 + r.Float32()}))
+		inv, ok := m.Inverse()
+		if !ok {
+			t.Fatalf("%v isn't invertible", m)
+		}
+		for _, product := range []Mat3{inv.Mul(m), m.Mul(inv)} {
+			for j := range product {
+				if !near(product[j], Identity3()[j]) {
+					t.Fatalf("inverse times m is %v, want the identity", product)
+				}
+			}
+		}
+	}
+
+	if _, ok := Scale3(vector3.Vector3{X: 1, Y: 0, Z: 1}).Inverse(); ok {
+		t.Error("inverted a singular matrix")
+	}
+}
+
+func TestMat4Inverse(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		m := Translate4(randomVector(r).Mult(10)).Mul(randomRotation(r).Mat4()).Mul(Scale4(vector3.Vector3{X: 0.5 + r.Float32(), Y: 0.5 + r.Float32(), Z: 0.5 + r.Float32()}))
+		inv, ok := m.Inverse()
+		if !ok {
+			t.Fatalf("%v isn't invertible", m)
+		}
+		product := inv.Mul(m)
+		for j := range product {
+			if !near(product[j], Identity4()[j]) {
+				t.Fatalf("inverse times m is %v, want the identity", product)
+			}
+		}
+	}
+
+	if _, ok := (Mat4{}).Inverse(); ok {
+		t.Error("inverted the zero matrix")
+	}
+}
+
+func TestQuaternionMatrixRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		q := randomRotation(r)
+		back := FromMat3(q.Mat3())
+		if !sameRotation(q, back) {
+			t.Fatalf("%v went through a matrix and came back as %v", q, back)
+		}
+
+		// the quaternion and its matrix must rotate vectors the same way
+		v := randomVector(r)
+		if !nearVec(q.Rotate(v), q.Mat3().MulVector3(v)) {
+			t.Fatalf("%v rotates %v to %v but its matrix gives %v", q, v, q.Rotate(v), q.Mat3().MulVector3(v))
+		}
+	}
+
+	// the four branches of FromMat3, half turns have a zero trace
+	axes := []vector3.Vector3{{X: 1}, {Y: 1}, {Z: 1}, {X: 1, Y: 1, Z: 1}}
+	for _, axis := range axes {
+		for _, angle := range []float32{0, math.Pi / 2, math.Pi} {
+			q := FromAxisAngle(axis, angle)
+			if back := FromMat3(Rotate3(axis, angle)); !sameRotation(q, back) {
+				t.Errorf("rotation of %v around %v came back as %v, want %v", angle, axis, back, q)
+			}
+		}
+	}
+}
+
+func TestRotate3ZeroAxis(t *testing.T) {
+	if m := Rotate3(vector3.Vector3{}, 1); m != Identity3() {
+		t.Errorf("rotating around a zero axis gives %v, want the identity", m)
+	}
+	if q := FromAxisAngle(vector3.Vector3{}, 1); q != IdentityQuaternion() {
+		t.Errorf("quaternion around a zero axis is %v, want the identity", q)
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	axis := vector3.Vector3{X: 1, Y: 2, Z: -1}
+	a := FromAxisAngle(axis, 0.3)
+	b := FromAxisAngle(axis, 1.9)
+
+	if q := Slerp(a, b, 0); !sameRotation(q, a) {
+		t.Errorf("slerp at 0 is %v, want %v", q, a)
+	}
+	if q := Slerp(a, b, 1); !sameRotation(q, b) {
+		t.Errorf("slerp at 1 is %v, want %v", q, b)
+	}
+	if q, want := Slerp(a, b, 0.5), FromAxisAngle(axis, 1.1); !sameRotation(q, want) {
+		t.Errorf("slerp midpoint is %v, want %v", q, want)
+	}
+	if q, want := Slerp(a, b, 0.25), FromAxisAngle(axis, 0.7); !sameRotation(q, want) {
+		t.Errorf("slerp at a quarter is %v, want %v", q, want)
+	}
+
+	// -b is the same rotation as b, so it must take the same short path
+	negB := Quaternion{-b.X, -b.Y, -b.Z, -b.W}
+	if q, want := Slerp(a, negB, 0.5), FromAxisAngle(axis, 1.1); !sameRotation(q, want) {
+		t.Errorf("slerp to -b midpoint is %v, want %v", q, want)
+	}
+
+	// nearly parallel rotations take the lerp branch, which must still be a unit rotation in between
+	c := FromAxisAngle(axis, 0.3001)
+	q := Slerp(a, c, 0.5)
+	if !near(q.Length(), 1) || !sameRotation(q, FromAxisAngle(axis, 0.30005)) {
+		t.Errorf("near parallel slerp is %v with length %v", q, q.Length())
+	}
+}
+
+func TestLookAt(t *testing.T) {
+	eye := vector3.Vector3{X: 1, Y: 2, Z: 5}
+	center := vector3.Vector3{X: 1, Y: 2, Z: 0}
+	view := LookAt(eye, center, vector3.Vector3{Y: 1})
+
+	tests := []struct {
+		world, camera vector3.Vector3
+	}{
+		// the eye is the origin and the camera looks down -z
+		{eye, vector3.Vector3{}},
+		{center, vector3.Vector3{Z: -5}},
+		// up stays up and right stays right when looking down -z
+		{vector3.Vector3{X: 1, Y: 3, Z: 5}, vector3.Vector3{Y: 1}},
+		{vector3.Vector3{X: 2, Y: 2, Z: 5}, vector3.Vector3{X: 1}},
+	}
+	for _, tt := range tests {
+		if got := view.MulPoint(tt.world); !nearVec(got, tt.camera) {
+			t.Errorf("%v is at %v in camera space, want %v", tt.world, got, tt.camera)
+		}
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	const near, far = 1, 10
+	proj := Perspective(math.Pi/2, 2, near, far)
+
+	tests := []struct {
+		camera, ndc vector3.Vector3
+	}{
+		// the near and far planes map to -1 and 1
+		{vector3.Vector3{Z: -near}, vector3.Vector3{Z: -1}},
+		{vector3.Vector3{Z: -far}, vector3.Vector3{Z: 1}},
+		// a 90 degree field of view reaches the top edge at y = -z, the aspect halves x
+		{vector3.Vector3{Y: 4, Z: -4}, vector3.Vector3{Y: 1, Z: 2.0 / 3}},
+		{vector3.Vector3{X: 8, Z: -4}, vector3.Vector3{X: 1, Z: 2.0 / 3}},
+	}
+	for _, tt := range tests {
+		if got := proj.MulPoint(tt.camera); !nearVec(got, tt.ndc) {
+			t.Errorf("%v projects to %v, want %v", tt.camera, got, tt.ndc)
+		}
+	}
+
+	// w carries the depth for the perspective divide
+	v := proj.MulVector4(vector4.Vector4{X: 0, Y: 0, Z: -4, W: 1})
+	if v.W != 4 {
+		t.Errorf("w is %v, want 4", v.W)
+	}
+}
+
+func TestOrtho(t *testing.T) {
+	proj := Ortho(0, 800, 600, 0, -1, 1)
+	if got := proj.MulPoint(vector3.Vector3{X: 0, Y: 0}); !nearVec(got, vector3.Vector3{X: -1, Y: 1}) {
+		t.Errorf("top left is %v, want -1, 1", got)
+	}
+	if got := proj.MulPoint(vector3.Vector3{X: 800, Y: 600}); !nearVec(got, vector3.Vector3{X: 1, Y: -1}) {
+		t.Errorf("bottom right is %v, want 1, -1", got)
+	}
+}
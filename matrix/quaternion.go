@@ -0,0 +1,135 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Quaternion is a rotation, X, Y and Z are the vector part and W the scalar part
+type Quaternion struct {
+	X, Y, Z, W float32
+}
+
+// IdentityQuaternion returns the rotation that does nothing
+func IdentityQuaternion() Quaternion {
+	return Quaternion{0, 0, 0, 1}
+}
+
+// FromAxisAngle returns a rotation of angle radians anticlockwise around axis,
+// a zero axis gives the identity
+func FromAxisAngle(axis vector3.Vector3, angle float32) Quaternion {
+	if axis == (vector3.Vector3{}) {
+		return IdentityQuaternion()
+	}
+	a := axis.Normalize()
+	sin, cos := math.Sincos(float64(angle) / 2)
+	s := float32(sin)
+	return Quaternion{a.X * s, a.Y * s, a.Z * s, float32(cos)}
+}
+
+// FromMat3 returns the rotation a rotation matrix performs, m shouldn't contain any scaling
+func FromMat3(m Mat3) Quaternion {
+	m00, m11, m22 := m.At(0, 0), m.At(1, 1), m.At(2, 2)
+	// work from the largest of w, x, y and z so the square root is never near zero
+	var q Quaternion
+	switch trace := m00 + m11 + m22; {
+	case trace > 0:
+		s := float32(math.Sqrt(float64(trace+1))) * 2
+		q = Quaternion{(m.At(2, 1) - m.At(1, 2)) / s, (m.At(0, 2) - m.At(2, 0)) / s, (m.At(1, 0) - m.At(0, 1)) / s, s / 4}
+	case m00 > m11 && m00 > m22:
+		s := float32(math.Sqrt(float64(1+m00-m11-m22))) * 2
+		q = Quaternion{s / 4, (m.At(0, 1) + m.At(1, 0)) / s, (m.At(0, 2) + m.At(2, 0)) / s, (m.At(2, 1) - m.At(1, 2)) / s}
+	case m11 > m22:
+		s := float32(math.Sqrt(float64(1+m11-m00-m22))) * 2
+		q = Quaternion{(m.At(0, 1) + m.At(1, 0)) / s, s / 4, (m.At(1, 2) + m.At(2, 1)) / s, (m.At(0, 2) - m.At(2, 0)) / s}
+	default:
+		s := float32(math.Sqrt(float64(1+m22-m00-m11))) * 2
+		q = Quaternion{(m.At(0, 2) + m.At(2, 0)) / s, (m.At(1, 2) + m.At(2, 1)) / s, s / 4, (m.At(1, 0) - m.At(0, 1)) / s}
+	}
+	return q.Normalize()
+}
+
+// Mul returns the rotation q * r, which applies r first and then q
+func (q Quaternion) Mul(r Quaternion) Quaternion {
+	return Quaternion{
+		q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+		q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+	}
+}
+
+// Conjugate returns q with its vector part negated, for a unit quaternion this is the opposite rotation
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Dot returns the dot product of two quaternions
+func (q Quaternion) Dot(r Quaternion) float32 {
+	return q.X*r.X + q.Y*r.Y + q.Z*r.Z + q.W*r.W
+}
+
+// Length returns the magnitude of q, rotations have length 1
+func (q Quaternion) Length() float32 {
+	return float32(math.Sqrt(float64(q.Dot(q))))
+}
+
+// Normalize returns q with length 1, a zero quaternion becomes the identity
+func (q Quaternion) Normalize() Quaternion {
+	length := q.Length()
+	if length == 0 {
+		return IdentityQuaternion()
+	}
+	return Quaternion{q.X / length, q.Y / length, q.Z / length, q.W / length}
+}
+
+// Rotate returns v rotated by q, q should have length 1
+func (q Quaternion) Rotate(v vector3.Vector3) vector3.Vector3 {
+	// v + 2w(u x v) + 2u x (u x v) where u is the vector part
+	u := vector3.Vector3{X: q.X, Y: q.Y, Z: q.Z}
+	t := u.Cross(v).Mult(2)
+	return v.Add(t.Mult(q.W)).Add(u.Cross(t))
+}
+
+// Slerp interpolates between two rotations along the shortest arc at a constant speed
+func Slerp(a, b Quaternion, pct float32) Quaternion {
+	cos := a.Dot(b)
+	// q and -q are the same rotation, flip b so we take the short way round
+	if cos < 0 {
+		b = Quaternion{-b.X, -b.Y, -b.Z, -b.W}
+		cos = -cos
+	}
+
+	var wa, wb float32
+	if cos > 0.9995 {
+		// nearly the same rotation, sin is too small to divide by so lerp instead
+		wa, wb = 1-pct, pct
+	} else {
+		theta := math.Acos(float64(cos))
+		sin := math.Sin(theta)
+		wa = float32(math.Sin((1-float64(pct))*theta) / sin)
+		wb = float32(math.Sin(float64(pct)*theta) / sin)
+	}
+	return Quaternion{
+		wa*a.X + wb*b.X,
+		wa*a.Y + wb*b.Y,
+		wa*a.Z + wb*b.Z,
+		wa*a.W + wb*b.W,
+	}.Normalize()
+}
+
+// Mat3 returns the rotation matrix for q, q should have length 1
+func (q Quaternion) Mat3() Mat3 {
+	x, y, z, w := q.X, q.Y, q.Z, q.W
+	return rows3(
+		1-2*(y*y+z*z), 2*(x*y-z*w), 2*(x*z+y*w),
+		2*(x*y+z*w), 1-2*(x*x+z*z), 2*(y*z-x*w),
+		2*(x*z-y*w), 2*(y*z+x*w), 1-2*(x*x+y*y),
+	)
+}
+
+// Mat4 returns the rotation matrix for q, q should have length 1
+func (q Quaternion) Mat4() Mat4 {
+	return q.Mat3().Mat4()
+}
@@ -0,0 +1,111 @@
+package noise
+
+// snoise2Scale roughly maps the output of Snoise2 onto the range -1 to 1,
+// the largest magnitude Snoise2 reaches is about 0.0221 or 1/45
+const snoise2Scale = 45
+
+// gradVec2 returns the gradient vector grad2 takes the dot product with
+func gradVec2(hash uint8) (gx, gy float32) {
+	h := hash & 7
+	var u, v float32 = 1, 2
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	if h < 4 {
+		return u, v
+	}
+	return v, u
+}
+
+// Snoise2Deriv generates a simplex noise along with its partial derivatives in x and y,
+// worked out analytically from the three corner contributions
+func Snoise2Deriv(x, y float32) (value, dx, dy float32) {
+
+	const F2 float32 = 0.366025403 // F2 = 0.5*(sqrt(3.0)-1.0)
+	const G2 float32 = 0.211324865 // G2 = (3.0-Math.sqrt(3.0))/6.0
+
+	// Skew the input space to determine which simplex cell we're in
+	s := (x + y) * F2
+	i := fastFloor(x + s)
+	j := fastFloor(y + s)
+
+	t := float32(i+j) * G2
+	x0 := x - (float32(i) - t)
+	y0 := y - (float32(j) - t)
+
+	var i1, j1 uint8
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+
+	x1 := x0 - float32(i1) + G2
+	y1 := y0 - float32(j1) + G2
+	x2 := x0 - 1.0 + 2.0*G2
+	y2 := y0 - 1.0 + 2.0*G2
+
+	ii := uint8(i)
+	jj := uint8(j)
+
+	corners := [3]struct {
+		x, y float32
+		hash uint8
+	}{
+		{x0, y0, perm[ii+perm[jj]]},
+		{x1, y1, perm[ii+i1+perm[jj+j1]]},
+		{x2, y2, perm[ii+1+perm[jj+1]]},
+	}
+
+	// Each corner contributes n = t^4 * (g . d) where t = 0.5 - |d|^2,
+	// so dn/dx = t^4 * gx - 8 * t^3 * x * (g . d) and likewise for y
+	for _, c := range corners {
+		t := 0.5 - c.x*c.x - c.y*c.y
+		if t < 0 {
+			continue
+		}
+		gx, gy := gradVec2(c.hash)
+		dot := gx*c.x + gy*c.y
+		t2 := t * t
+		t4 := t2 * t2
+		value += t4 * dot
+		dx += t4*gx - 8*t2*t*c.x*dot
+		dy += t4*gy - 8*t2*t*c.y*dot
+	}
+
+	return value, dx, dy
+}
+
+// Fbm2Deriv generates fractal brownian motion noise along with its partial derivatives
+func Fbm2Deriv(x, y, frequency, lacunarity, gain float32, octaves int) (value, dx, dy float32) {
+	amplitude := float32(1.0)
+	for i := 0; i < octaves; i++ {
+		n, ndx, ndy := Snoise2Deriv(x*frequency, y*frequency)
+		value += n * amplitude
+		dx += ndx * amplitude * frequency
+		dy += ndy * amplitude * frequency
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	return value, dx, dy
+}
+
+// DampedFbm2 generates fractal brownian motion noise where each octave is damped by the
+// slope accumulated from the octaves before it, flattening valleys and sharpening ridges.
+// The slope is taken in noise space so the look doesn't change with frequency.
+func DampedFbm2(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
+	var sum, dx, dy float32
+	amplitude := float32(1.0)
+	for i := 0; i < octaves; i++ {
+		n, ndx, ndy := Snoise2Deriv(x*frequency, y*frequency)
+		dx += ndx * snoise2Scale
+		dy += ndy * snoise2Scale
+		sum += amplitude * n / (1 + dx*dx + dy*dy)
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	return sum
+}
@@ -0,0 +1,114 @@
+package noise
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	// step is the distance used for central differences
+	step = 1e-3
+	// derivTolerance allows for float32 rounding in the differences
+	derivTolerance = 2e-3
+)
+
+func centralDiff(f func(x, y float32) float32, x, y float32) (dx, dy float32) {
+	dx = (f(x+step, y) - f(x-step, y)) / (2 * step)
+	dy = (f(x, y+step) - f(x, y-step)) / (2 * step)
+	return dx, dy
+}
+
+// unskew turns a point in skewed simplex grid space back into input space
+func unskew(sx, sy float32) (x, y float32) {
+	const G2 float32 = 0.211324865
+	t := (sx + sy) * G2
+	return sx - t, sy - t
+}
+
+// derivPoints returns a grid of points plus points just either side of the edges
+// between simplex cells, where the corners that contribute change
+func derivPoints() [][2]float32 {
+	var points [][2]float32
+	for y := float32(-3.1); y < 3; y += 0.37 {
+		for x := float32(-3.3); x < 3; x += 0.41 {
+			points = append(points, [2]float32{x, y})
+		}
+	}
+
+	const near = 0.01
+	for j := -2; j <= 2; j++ {
+		for i := -2; i <= 2; i++ {
+			for _, a := range []float32{0.2, 0.5, 0.8} {
+				fi, fj := float32(i), float32(j)
+				for _, side := range []float32{-near, near} {
+					// the diagonal splitting a cell into two triangles
+					x, y := unskew(fi+a+side, fj+a-side)
+					points = append(points, [2]float32{x, y})
+					// the cell's bottom and left edges
+					x, y = unskew(fi+a, fj+side)
+					points = append(points, [2]float32{x, y})
+					x, y = unskew(fi+side, fj+a)
+					points = append(points, [2]float32{x, y})
+				}
+			}
+		}
+	}
+	return points
+}
+
+func close32(a, b, tolerance float32) bool {
+	return math.Abs(float64(a-b)) <= float64(tolerance)
+}
+
+func TestSnoise2DerivMatchesFiniteDifferences(t *testing.T) {
+	for _, p := range derivPoints() {
+		x, y := p[0], p[1]
+		value, dx, dy := Snoise2Deriv(x, y)
+		if want := Snoise2(x, y); !close32(value, want, 1e-6) {
+			t.Errorf("value at %v, %v is %v, Snoise2 gives %v", x, y, value, want)
+		}
+
+		wantDx, wantDy := centralDiff(Snoise2, x, y)
+		if !close32(dx, wantDx, derivTolerance) || !close32(dy, wantDy, derivTolerance) {
+			t.Errorf("derivative at %v, %v is %v, %v, central difference gives %v, %v", x, y, dx, dy, wantDx, wantDy)
+		}
+	}
+}
+
+func TestFbm2DerivMatchesFiniteDifferences(t *testing.T) {
+	const frequency, lacunarity, gain, octaves = 0.7, 2, 0.5, 4
+	fbm := func(x, y float32) float32 { return Fbm2(x, y, frequency, lacunarity, gain, octaves) }
+
+	for _, p := range derivPoints() {
+		x, y := p[0], p[1]
+		value, dx, dy := Fbm2Deriv(x, y, frequency, lacunarity, gain, octaves)
+		if want := fbm(x, y); !close32(value, want, 1e-6) {
+			t.Errorf("value at %v, %v is %v, Fbm2 gives %v", x, y, value, want)
+		}
+
+		// the highest octave is 8 times the frequency, so the differences are that much rougher
+		wantDx, wantDy := centralDiff(fbm, x, y)
+		if !close32(dx, wantDx, 8*derivTolerance) || !close32(dy, wantDy, 8*derivTolerance) {
+			t.Errorf("derivative at %v, %v is %v, %v, central difference gives %v, %v", x, y, dx, dy, wantDx, wantDy)
+		}
+	}
+}
+
+func TestSnoise2Scale(t *testing.T) {
+	// snoise2Scale is 1 over the largest magnitude Snoise2 reaches
+	var largest float32
+	for y := 0; y < 500; y++ {
+		for x := 0; x < 500; x++ {
+			v := Snoise2(float32(x)*0.0731, float32(y)*0.0731)
+			if v < 0 {
+				v = -v
+			}
+			if v > largest {
+				largest = v
+			}
+		}
+	}
+	if scaled := largest * snoise2Scale; scaled < 0.9 || scaled > 1.05 {
+		t.Errorf("largest Snoise2 value %v scales to %v, want about 1", largest, scaled)
+	}
+}
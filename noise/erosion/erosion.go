@@ -0,0 +1,461 @@
+package erosion
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// lanes is the number of independent droplet streams per iteration.
+// Each lane writes to its own buffers which are merged in lane order,
+// so the result only depends on the seed and not on the number of workers.
+const lanes = 8
+
+// Config configures an erosion run
+type Config struct {
+	// Seed makes the droplet starting positions deterministic
+	Seed int64
+	// Iterations is the number of hydraulic and thermal passes
+	Iterations int
+	// Droplets is the number of water droplets simulated per iteration
+	Droplets int
+	// Workers is the number of goroutines to use, 0 uses one per CPU
+	Workers int
+
+	// Inertia is how much a droplet keeps its direction, 0 to 1
+	Inertia float32
+	// Capacity scales how much sediment a droplet can carry
+	Capacity float32
+	// MinCapacity stops droplets on flat ground eroding nothing
+	MinCapacity float32
+	// Deposition is the fraction of surplus sediment dropped each step
+	Deposition float32
+	// Erosion is the fraction of free capacity picked up each step
+	Erosion float32
+	// Evaporation is the fraction of water lost each step
+	Evaporation float32
+	// Gravity accelerates droplets going downhill
+	Gravity float32
+	// MaxLifetime is the maximum number of steps a droplet takes
+	MaxLifetime int
+	// Radius is the radius in pixels a droplet erodes around itself
+	Radius int
+	// BatchSize is the number of droplets per lane between merging the lanes,
+	// smaller batches stop lanes carving the same channel twice
+	BatchSize int
+
+	// Talus is the height difference between neighbours above which material slides,
+	// in heights normalized to the range 0 to 1
+	Talus float32
+	// ThermalRate is the fraction of the excess that slides each iteration
+	ThermalRate float32
+}
+
+// DefaultConfig returns a config that gives reasonable results on noise heightmaps
+func DefaultConfig() Config {
+	return Config{
+		Seed:        1,
+		Iterations:  10,
+		Droplets:    20000,
+		Inertia:     0.05,
+		Capacity:    4,
+		MinCapacity: 0.01,
+		Deposition:  0.3,
+		Erosion:     0.3,
+		Evaporation: 0.01,
+		Gravity:     4,
+		MaxLifetime: 30,
+		Radius:      3,
+		BatchSize:   64,
+		Talus:       0.004,
+		ThermalRate: 0.5,
+	}
+}
+
+// Result is an eroded heightmap
+type Result struct {
+	// Heights is the eroded map in the same range as the input
+	Heights []float32
+	// Sediment is how much material was deposited on each pixel, normalized to 0 to 1
+	Sediment []float32
+	// Flow is how much water passed over each pixel, normalized to 0 to 1
+	Flow []float32
+	// Min and Max are the range of Heights
+	Min, Max float32
+}
+
+// Erode runs hydraulic and thermal erosion over a w by h heightmap, heights isn't modified.
+// It returns the context's error if the context is cancelled before it is done.
+func Erode(ctx context.Context, heights []float32, w, h int, cfg Config) (Result, error) {
+	if w < 2 || h < 2 || len(heights) < w*h {
+		return Result{}, errors.New("erosion: heightmap too small")
+	}
+
+	numRoutines := cfg.Workers
+	if numRoutines <= 0 {
+		numRoutines = runtime.NumCPU()
+	}
+
+	min, max := minMax(heights[:w*h])
+	rng := max - min
+	if rng == 0 {
+		rng = 1
+	}
+
+	m := &terrain{w: w, h: h, heights: make([]float32, w*h)}
+	for i := range m.heights {
+		m.heights[i] = (heights[i] - min) / rng
+	}
+	var ls [lanes]*lane
+	for i := range ls {
+		ls[i] = &lane{
+			delta:    make([]float32, w*h),
+			flow:     make([]float32, w*h),
+			sediment: make([]float32, w*h),
+		}
+	}
+	slide := make([]float32, w*h)
+	b := newBrush(cfg.Radius)
+	batch := cfg.BatchSize * lanes
+	if batch <= 0 {
+		batch = cfg.Droplets
+	}
+
+	for iteration := 0; iteration < cfg.Iterations; iteration++ {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+
+		for first := 0; first < cfg.Droplets; first += batch {
+			last := first + batch
+			if last > cfg.Droplets {
+				last = cfg.Droplets
+			}
+			parallel(lanes, numRoutines, func(start, end int) {
+				for l := start; l < end; l++ {
+					ls[l].simulate(m, &cfg, b, iteration, first+l, last)
+				}
+			})
+			parallel(h, numRoutines, func(start, end int) {
+				for i := start * w; i < end*w; i++ {
+					for _, l := range ls {
+						m.heights[i] += l.delta[i]
+						l.delta[i] = 0
+					}
+				}
+			})
+		}
+
+		if cfg.Talus > 0 && cfg.ThermalRate > 0 {
+			m.thermal(&cfg, slide, numRoutines)
+		}
+	}
+
+	result := Result{
+		Heights:  make([]float32, w*h),
+		Sediment: make([]float32, w*h),
+		Flow:     make([]float32, w*h),
+	}
+	for i := range result.Heights {
+		result.Heights[i] = m.heights[i]*rng + min
+		for _, l := range ls {
+			result.Flow[i] += l.flow[i]
+			result.Sediment[i] += l.sediment[i]
+		}
+	}
+	normalize(result.Flow)
+	normalize(result.Sediment)
+	result.Min, result.Max = minMax(result.Heights)
+	return result, nil
+}
+
+type terrain struct {
+	w, h    int
+	heights []float32
+}
+
+type lane struct {
+	delta    []float32
+	flow     []float32
+	sediment []float32
+}
+
+func (l *lane) height(m *terrain, i int) float32 {
+	return m.heights[i] + l.delta[i]
+}
+
+// heightAndGradient returns the bilinearly interpolated height and gradient at x, y
+func (l *lane) heightAndGradient(m *terrain, x, y float32) (height, gx, gy float32) {
+	cx := int(x)
+	cy := int(y)
+	u := x - float32(cx)
+	v := y - float32(cy)
+
+	i := cy*m.w + cx
+	nw := l.height(m, i)
+	ne := l.height(m, i+1)
+	sw := l.height(m, i+m.w)
+	se := l.height(m, i+m.w+1)
+
+	gx = (ne-nw)*(1-v) + (se-sw)*v
+	gy = (sw-nw)*(1-u) + (se-ne)*u
+	height = nw*(1-u)*(1-v) + ne*u*(1-v) + sw*(1-u)*v + se*u*v
+	return height, gx, gy
+}
+
+// add spreads amount over the four pixels around x, y
+func (l *lane) add(m *terrain, x, y, amount float32) {
+	cx := int(x)
+	cy := int(y)
+	u := x - float32(cx)
+	v := y - float32(cy)
+	i := cy*m.w + cx
+	l.delta[i] += amount * (1 - u) * (1 - v)
+	l.delta[i+1] += amount * u * (1 - v)
+	l.delta[i+m.w] += amount * (1 - u) * v
+	l.delta[i+m.w+1] += amount * u * v
+}
+
+// erode removes amount around pixel cx, cy spread by the brush
+func (l *lane) erode(m *terrain, b *brush, cx, cy int, amount float32) {
+	var total float32
+	for k, offset := range b.offsets {
+		x, y := cx+offset[0], cy+offset[1]
+		if x >= 0 && y >= 0 && x < m.w && y < m.h {
+			total += b.weights[k]
+		}
+	}
+	for k, offset := range b.offsets {
+		x, y := cx+offset[0], cy+offset[1]
+		if x >= 0 && y >= 0 && x < m.w && y < m.h {
+			l.delta[y*m.w+x] -= amount * b.weights[k] / total
+		}
+	}
+}
+
+// brush is the set of pixels around a droplet it erodes, weighted by closeness
+type brush struct {
+	offsets [][2]int
+	weights []float32
+}
+
+func newBrush(radius int) *brush {
+	if radius < 1 {
+		radius = 1
+	}
+	b := &brush{}
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			dist := float32(math.Sqrt(float64(x*x + y*y)))
+			if dist < float32(radius) {
+				b.offsets = append(b.offsets, [2]int{x, y})
+				b.weights = append(b.weights, 1-dist/float32(radius))
+			}
+		}
+	}
+	return b
+}
+
+// simulate runs droplets first, first+lanes, ... up to last
+func (l *lane) simulate(m *terrain, cfg *Config, b *brush, iteration, first, last int) {
+	maxX := float32(m.w - 1)
+	maxY := float32(m.h - 1)
+
+	for d := first; d < last; d += lanes {
+		r := newRand(cfg.Seed, iteration, d)
+		x := r.float32() * maxX
+		y := r.float32() * maxY
+		var dirX, dirY, sediment float32
+		speed := float32(1)
+		water := float32(1)
+
+		for step := 0; step < cfg.MaxLifetime; step++ {
+			cx, cy := int(x), int(y)
+			height, gx, gy := l.heightAndGradient(m, x, y)
+
+			dirX = dirX*cfg.Inertia - gx*(1-cfg.Inertia)
+			dirY = dirY*cfg.Inertia - gy*(1-cfg.Inertia)
+			length := float32(math.Sqrt(float64(dirX*dirX + dirY*dirY)))
+			if length == 0 {
+				break
+			}
+			dirX /= length
+			dirY /= length
+
+			oldX, oldY := x, y
+			x += dirX
+			y += dirY
+			l.flow[cy*m.w+cx] += water
+			if x < 0 || y < 0 || x >= maxX || y >= maxY {
+				// the sediment is carried off the map
+				sediment = 0
+				break
+			}
+
+			newHeight, _, _ := l.heightAndGradient(m, x, y)
+			deltaHeight := newHeight - height
+
+			capacity := -deltaHeight * speed * water * cfg.Capacity
+			if capacity < cfg.MinCapacity {
+				capacity = cfg.MinCapacity
+			}
+
+			if sediment > capacity || deltaHeight > 0 {
+				// going uphill fills the pit behind, otherwise drop the surplus
+				amount := (sediment - capacity) * cfg.Deposition
+				if deltaHeight > 0 {
+					amount = sediment
+					if deltaHeight < amount {
+						amount = deltaHeight
+					}
+				}
+				sediment -= amount
+				l.add(m, oldX, oldY, amount)
+				l.sediment[cy*m.w+cx] += amount
+			} else {
+				amount := (capacity - sediment) * cfg.Erosion
+				if -deltaHeight < amount {
+					amount = -deltaHeight
+				}
+				sediment += amount
+				l.erode(m, b, cx, cy, amount)
+			}
+
+			speedSquared := speed*speed + deltaHeight*cfg.Gravity
+			if speedSquared < 0 {
+				speedSquared = 0
+			}
+			speed = float32(math.Sqrt(float64(speedSquared)))
+			water *= 1 - cfg.Evaporation
+		}
+
+		// the droplet dried up or stopped in a pit, drop what it was carrying
+		if sediment > 0 {
+			l.add(m, x, y, sediment)
+			l.sediment[int(y)*m.w+int(x)] += sediment
+		}
+	}
+}
+
+var neighbours = [8][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+
+// thermal slides material from every pixel to its lower neighbours where the slope is steeper than talus.
+// Each pixel's outflow is worked out first and then gathered, so rows can be done in parallel.
+func (m *terrain) thermal(cfg *Config, slide []float32, numRoutines int) {
+	w, h := m.w, m.h
+
+	// slide holds the total excess over talus of each pixel, outflow is a share of it
+	parallel(h, numRoutines, func(start, end int) {
+		for y := start; y < end; y++ {
+			for x := 0; x < w; x++ {
+				var total float32
+				for _, n := range neighbours {
+					nx, ny := x+n[0], y+n[1]
+					if nx < 0 || ny < 0 || nx >= w || ny >= h {
+						continue
+					}
+					diff := m.heights[y*w+x] - m.heights[ny*w+nx]
+					if diff > cfg.Talus {
+						total += diff - cfg.Talus
+					}
+				}
+				slide[y*w+x] = total
+			}
+		}
+	})
+
+	next := make([]float32, w*h)
+	parallel(h, numRoutines, func(start, end int) {
+		for y := start; y < end; y++ {
+			for x := 0; x < w; x++ {
+				i := y*w + x
+				v := m.heights[i]
+				for _, n := range neighbours {
+					nx, ny := x+n[0], y+n[1]
+					if nx < 0 || ny < 0 || nx >= w || ny >= h {
+						continue
+					}
+					j := ny*w + nx
+					diff := m.heights[i] - m.heights[j]
+					if diff > cfg.Talus {
+						v -= m.transfer(cfg, slide[i], diff)
+					} else if -diff > cfg.Talus {
+						v += m.transfer(cfg, slide[j], -diff)
+					}
+				}
+				next[i] = v
+			}
+		}
+	})
+	copy(m.heights, next)
+}
+
+// transfer is how much slides across a neighbour pair with height difference diff,
+// out of the sliding pixel's total excess
+func (m *terrain) transfer(cfg *Config, total, diff float32) float32 {
+	excess := diff - cfg.Talus
+	return cfg.ThermalRate * excess / 2 * excess / total
+}
+
+// parallel splits n items into contiguous ranges over numRoutines goroutines
+func parallel(n, numRoutines int, f func(start, end int)) {
+	if numRoutines > n {
+		numRoutines = n
+	}
+	var wg sync.WaitGroup
+	wg.Add(numRoutines)
+	for i := 0; i < numRoutines; i++ {
+		go func(i int) {
+			defer wg.Done()
+			f(i*n/numRoutines, (i+1)*n/numRoutines)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func minMax(values []float32) (min, max float32) {
+	min = float32(math.MaxFloat32)
+	max = float32(-math.MaxFloat32)
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
+
+func normalize(values []float32) {
+	_, max := minMax(values)
+	if max <= 0 {
+		return
+	}
+	for i := range values {
+		values[i] /= max
+	}
+}
+
+// splitmix64 so every droplet gets its own stream without allocating a rand.Source
+type random uint64
+
+func newRand(seed int64, iteration, droplet int) random {
+	r := random(uint64(seed)*0x9E3779B97F4A7C15 ^ uint64(iteration)<<32 ^ uint64(droplet))
+	r.next()
+	return r
+}
+
+func (r *random) next() uint64 {
+	*r += 0x9E3779B97F4A7C15
+	z := uint64(*r)
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+func (r *random) float32() float32 {
+	return float32(r.next()>>40) / (1 << 24)
+}
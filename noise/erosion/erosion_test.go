@@ -0,0 +1,143 @@
+package erosion
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const testW, testH = 64, 48
+
+func testHeights() []float32 {
+	rng := rand.New(rand.NewSource(1))
+	heights := make([]float32, testW*testH)
+	for y := 0; y < testH; y++ {
+		for x := 0; x < testW; x++ {
+			hill := float32(math.Sin(float64(x)*0.1) * math.Cos(float64(y)*0.13))
+			heights[y*testW+x] = 100*hill + rng.Float32()*5
+		}
+	}
+	return heights
+}
+
+func testConfig(workers int) Config {
+	cfg := DefaultConfig()
+	cfg.Iterations = 3
+	cfg.Droplets = 3000
+	cfg.Workers = workers
+	return cfg
+}
+
+func sameFloats(t *testing.T, name string, a, b []float32) {
+	t.Helper()
+	if len(a) != len(b) {
+		t.Fatalf("%s has %d values and %d values", name, len(a), len(b))
+	}
+	for i := range a {
+		if math.Float32bits(a[i]) != math.Float32bits(b[i]) {
+			t.Fatalf("%s %d is %v and %v", name, i, a[i], b[i])
+		}
+	}
+}
+
+func sameResults(t *testing.T, a, b Result) {
+	t.Helper()
+	sameFloats(t, "height", a.Heights, b.Heights)
+	sameFloats(t, "flow", a.Flow, b.Flow)
+	sameFloats(t, "sediment", a.Sediment, b.Sediment)
+	if a.Min != b.Min || a.Max != b.Max {
+		t.Fatalf("ranges are %v..%v and %v..%v", a.Min, a.Max, b.Min, b.Max)
+	}
+}
+
+func TestWorkersDontChangeResult(t *testing.T) {
+	heights := testHeights()
+	one, err := Erode(context.Background(), heights, testW, testH, testConfig(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eight, err := Erode(context.Background(), heights, testW, testH, testConfig(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameResults(t, one, eight)
+}
+
+func TestErodeRepeats(t *testing.T) {
+	heights := testHeights()
+	before := append([]float32(nil), heights...)
+	a, err := Erode(context.Background(), heights, testW, testH, testConfig(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Erode(context.Background(), heights, testW, testH, testConfig(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameResults(t, a, b)
+	sameFloats(t, "input", heights, before)
+
+	// and it did actually erode something
+	changed := false
+	for i := range heights {
+		if a.Heights[i] != heights[i] {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		t.Error("erosion didn't change the heightmap")
+	}
+}
+
+func TestThermalConservesMass(t *testing.T) {
+	heights := testHeights()
+	min, max := minMax(heights)
+	m := &terrain{w: testW, h: testH, heights: make([]float32, len(heights))}
+	for i := range heights {
+		m.heights[i] = (heights[i] - min) / (max - min)
+	}
+	cfg := DefaultConfig()
+	sum := func() float64 {
+		var total float64
+		for _, v := range m.heights {
+			total += float64(v)
+		}
+		return total
+	}
+
+	before := sum()
+	slide := make([]float32, len(heights))
+	for i := 0; i < 20; i++ {
+		m.thermal(&cfg, slide, 4)
+	}
+	if after := sum(); math.Abs(after-before) > 1e-3 {
+		t.Errorf("total height went from %v to %v", before, after)
+	}
+}
+
+func TestErodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		heights []float32
+		w, h    int
+	}{
+		{"too narrow", make([]float32, 10), 1, 10},
+		{"too short", make([]float32, 10), 10, 1},
+		{"short slice", make([]float32, 15), 4, 4},
+	}
+	for _, tt := range tests {
+		if _, err := Erode(context.Background(), tt.heights, tt.w, tt.h, testConfig(1)); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestErodeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Erode(ctx, testHeights(), testW, testH, testConfig(2)); err != ctx.Err() {
+		t.Errorf("error is %v, want %v", err, ctx.Err())
+	}
+}
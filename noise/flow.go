@@ -0,0 +1,51 @@
+package noise
+
+// flowScale brings the speed of a FlowField with Strength 1 to around 1
+const flowScale = 5
+
+// FlowField is a divergence free velocity field made by taking the curl of fractal simplex noise.
+// Things moving along it swirl around but never bunch up in sinks or spread out from sources.
+type FlowField struct {
+	Frequency  float32
+	Lacunarity float32
+	Gain       float32
+	Octaves    int
+	// TimeScale is how fast the field changes over time
+	TimeScale float32
+	// Strength scales the velocity, at 1 the average speed is around 1
+	Strength float32
+}
+
+// potential returns the partial derivatives of the noise potential on one plane.
+// The noise is divided by frequency so speeds don't change with the frequency,
+// a zero frequency is a flat potential and so a still field.
+func (f *FlowField) potential(a, b, offset float32) (da, db float32) {
+	if f.Frequency == 0 {
+		return 0, 0
+	}
+	_, da, db = Fbm2Deriv(a+offset, b-offset, f.Frequency, f.Lacunarity, f.Gain, f.Octaves)
+	scale := f.Strength * flowScale / f.Frequency
+	return da * scale, db * scale
+}
+
+// Curl2 returns the velocity at x, y at time t.
+// It is the curl of a scalar potential psi, (dpsi/dy, -dpsi/dx).
+func (f *FlowField) Curl2(x, y, t float32) (vx, vy float32) {
+	dx, dy := f.potential(x, y, t*f.TimeScale)
+	return dy, -dx
+}
+
+// Curl3 returns the velocity at x, y, z at time t.
+// It is the curl of the vector potential (psi1(y, z), psi2(z, x), psi3(x, y)),
+// each component being noise over one plane, which keeps it divergence free.
+func (f *FlowField) Curl3(x, y, z, t float32) (vx, vy, vz float32) {
+	offset := t * f.TimeScale
+	// the planes are shifted apart so the three potentials aren't the same noise
+	d1dy, d1dz := f.potential(y, z, offset)
+	d2dz, d2dx := f.potential(z+101.7, x+33.3, offset)
+	d3dx, d3dy := f.potential(x+57.1, y+211.9, offset)
+	vx = d3dy - d2dz
+	vy = d1dz - d3dx
+	vz = d2dx - d1dy
+	return vx, vy, vz
+}
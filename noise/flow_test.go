@@ -0,0 +1,35 @@
+package noise
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFlowFieldZeroFrequency(t *testing.T) {
+	f := FlowField{Lacunarity: 2, Gain: 0.5, Octaves: 3, TimeScale: 1, Strength: 1}
+	vx, vy := f.Curl2(12.5, -3, 1)
+	if vx != 0 || vy != 0 {
+		t.Errorf("Curl2 with zero frequency is %v, %v, want a still field", vx, vy)
+	}
+	vx, vy, vz := f.Curl3(12.5, -3, 7, 1)
+	if vx != 0 || vy != 0 || vz != 0 {
+		t.Errorf("Curl3 with zero frequency is %v, %v, %v, want a still field", vx, vy, vz)
+	}
+}
+
+func TestFlowFieldDivergenceFree(t *testing.T) {
+	f := FlowField{Frequency: 0.05, Lacunarity: 2, Gain: 0.5, Octaves: 2, TimeScale: 1, Strength: 1}
+	const h = 0.01
+	for y := float32(-20); y < 20; y += 3.7 {
+		for x := float32(-20); x < 20; x += 4.1 {
+			vx1, _ := f.Curl2(x+h, y, 0)
+			vx0, _ := f.Curl2(x-h, y, 0)
+			_, vy1 := f.Curl2(x, y+h, 0)
+			_, vy0 := f.Curl2(x, y-h, 0)
+			div := (vx1-vx0)/(2*h) + (vy1-vy0)/(2*h)
+			if math.IsNaN(float64(div)) || math.Abs(float64(div)) > 0.05 {
+				t.Errorf("divergence at %v, %v is %v", x, y, div)
+			}
+		}
+	}
+}
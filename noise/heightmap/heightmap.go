@@ -0,0 +1,394 @@
+package heightmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sabith-th/games_with_go/noise"
+)
+
+// Format is a file format a heightmap can be saved in
+type Format int
+
+const (
+	// PNG8 is an 8 bit grayscale png
+	PNG8 Format = iota
+	// PNG16 is a 16 bit grayscale png
+	PNG16
+	// PGM is a 16 bit binary portable graymap
+	PGM
+	// RAW is little endian float32 values, row by row
+	RAW
+)
+
+// Params are the generation parameters stored alongside a heightmap
+type Params struct {
+	Module  *noise.ModuleSpec `json:"module,omitempty"`
+	OffsetX float32           `json:"offsetX"`
+	OffsetY float32           `json:"offsetY"`
+	Scale   float32           `json:"scale"`
+}
+
+// Heightmap is a block of noise values, Min and Max are the range the values span
+type Heightmap struct {
+	Width  int       `json:"width"`
+	Height int       `json:"height"`
+	Min    float32   `json:"min"`
+	Max    float32   `json:"max"`
+	Params *Params   `json:"params,omitempty"`
+	Data   []float32 `json:"-"`
+}
+
+// FromResult wraps the result of noise.MakeNoise as a heightmap, the options it was made
+// with are recorded as its Params. spec is the JSON form of opts.Module, it can be nil if
+// the graph was built in code.
+func FromResult(result noise.Result, opts noise.Options, spec *noise.ModuleSpec) *Heightmap {
+	data := result.Noise
+	if data == nil {
+		data = make([]float32, len(result.Noise64))
+		for i, v := range result.Noise64 {
+			data[i] = float32(v)
+		}
+	}
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	params := &Params{Module: spec, OffsetX: opts.OffsetX, OffsetY: opts.OffsetY, Scale: scale}
+	return &Heightmap{Width: opts.Width, Height: opts.Height, Min: result.Min, Max: result.Max, Params: params, Data: data}
+}
+
+// Normalized returns the value at index i rescaled from Min..Max to 0..1
+func (hm *Heightmap) Normalized(i int) float32 {
+	if hm.Max == hm.Min {
+		return 0
+	}
+	v := (hm.Data[i] - hm.Min) / (hm.Max - hm.Min)
+	if v < 0 {
+		return 0
+	} else if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// denormalize maps values in 0..1 back onto the range given by min and max
+func (hm *Heightmap) denormalize(min, max float32) {
+	for i, v := range hm.Data {
+		hm.Data[i] = min + v*(max-min)
+	}
+	hm.Min = min
+	hm.Max = max
+}
+
+// WritePNG8 writes the heightmap as an 8 bit grayscale png
+func WritePNG8(w io.Writer, hm *Heightmap) error {
+	err := checkSize(hm)
+	if err != nil {
+		return err
+	}
+	img := image.NewGray(image.Rect(0, 0, hm.Width, hm.Height))
+	for i := range hm.Data {
+		img.Pix[i] = uint8(hm.Normalized(i)*255 + 0.5)
+	}
+	return png.Encode(w, img)
+}
+
+// WritePNG16 writes the heightmap as a 16 bit grayscale png
+func WritePNG16(w io.Writer, hm *Heightmap) error {
+	err := checkSize(hm)
+	if err != nil {
+		return err
+	}
+	img := image.NewGray16(image.Rect(0, 0, hm.Width, hm.Height))
+	for i := range hm.Data {
+		v := uint16(hm.Normalized(i)*65535 + 0.5)
+		img.Pix[i*2] = uint8(v >> 8)
+		img.Pix[i*2+1] = uint8(v)
+	}
+	return png.Encode(w, img)
+}
+
+// ReadPNG reads a grayscale png, values are in the range 0 to 1
+func ReadPNG(r io.Reader) (*Heightmap, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	hm := &Heightmap{Width: w, Height: h, Min: 0, Max: 1, Data: make([]float32, w*h)}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			gray := color.Gray16Model.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray16)
+			hm.Data[y*w+x] = float32(gray.Y) / 65535
+		}
+	}
+	return hm, nil
+}
+
+// WritePGM writes the heightmap as a 16 bit binary pgm
+func WritePGM(w io.Writer, hm *Heightmap) error {
+	err := checkSize(hm)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	_, err = fmt.Fprintf(bw, "P5\n%d %d\n65535\n", hm.Width, hm.Height)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 2)
+	for i := range hm.Data {
+		binary.BigEndian.PutUint16(buf, uint16(hm.Normalized(i)*65535+0.5))
+		_, err = bw.Write(buf)
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadPGM reads an 8 or 16 bit binary pgm, values are in the range 0 to 1
+func ReadPGM(r io.Reader) (*Heightmap, error) {
+	br := bufio.NewReader(r)
+	magic, err := pgmToken(br)
+	if err != nil {
+		return nil, err
+	}
+	if magic != "P5" {
+		return nil, fmt.Errorf("heightmap: not a binary pgm, magic %q", magic)
+	}
+	// reading maxVal also reads the single whitespace byte that separates the
+	// header from the pixels
+	var header [3]int
+	for i := range header {
+		token, err := pgmToken(br)
+		if err != nil {
+			return nil, err
+		}
+		header[i], err = strconv.Atoi(token)
+		if err != nil {
+			return nil, fmt.Errorf("heightmap: invalid pgm header value %q", token)
+		}
+	}
+	w, h, maxVal := header[0], header[1], header[2]
+	if w <= 0 || h <= 0 || maxVal <= 0 || maxVal > 65535 {
+		return nil, fmt.Errorf("heightmap: invalid pgm header %dx%d max %d", w, h, maxVal)
+	}
+
+	bytesPerPixel := 1
+	if maxVal > 255 {
+		bytesPerPixel = 2
+	}
+	pixels := make([]byte, w*h*bytesPerPixel)
+	_, err = io.ReadFull(br, pixels)
+	if err != nil {
+		return nil, err
+	}
+
+	hm := &Heightmap{Width: w, Height: h, Min: 0, Max: 1, Data: make([]float32, w*h)}
+	for i := range hm.Data {
+		var v int
+		if bytesPerPixel == 2 {
+			v = int(binary.BigEndian.Uint16(pixels[i*2:]))
+		} else {
+			v = int(pixels[i])
+		}
+		hm.Data[i] = float32(v) / float32(maxVal)
+	}
+	return hm, nil
+}
+
+// pgmToken reads the next header token, skipping whitespace and # comments, and
+// the whitespace byte after it
+func pgmToken(br *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF && len(token) > 0 {
+			return string(token), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case b == '#':
+			if len(token) > 0 {
+				// a comment straight after a token ends it
+				return string(token), br.UnreadByte()
+			}
+			_, err = br.ReadString('\n')
+			if err != nil {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f':
+			if len(token) > 0 {
+				return string(token), nil
+			}
+		default:
+			token = append(token, b)
+		}
+	}
+}
+
+// checkSize returns an error if the heightmap doesn't have a value for every pixel
+func checkSize(hm *Heightmap) error {
+	if hm.Width <= 0 || hm.Height <= 0 || len(hm.Data) != hm.Width*hm.Height {
+		return fmt.Errorf("heightmap: %dx%d heightmap has %d values", hm.Width, hm.Height, len(hm.Data))
+	}
+	return nil
+}
+
+// WriteRaw writes the heightmap values as little endian float32
+func WriteRaw(w io.Writer, hm *Heightmap) error {
+	err := checkSize(hm)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	err = binary.Write(bw, binary.LittleEndian, hm.Data)
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ReadRaw reads little endian float32 values, the sidecar gives the dimensions and range
+func ReadRaw(r io.Reader, sidecar io.Reader) (*Heightmap, error) {
+	hm, err := ReadSidecar(sidecar)
+	if err != nil {
+		return nil, err
+	}
+	hm.Data = make([]float32, hm.Width*hm.Height)
+	err = binary.Read(bufio.NewReader(r), binary.LittleEndian, hm.Data)
+	if err != nil {
+		return nil, err
+	}
+	return hm, nil
+}
+
+// WriteSidecar writes the dimensions, range and parameters of the heightmap as JSON
+func WriteSidecar(w io.Writer, hm *Heightmap) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(hm)
+}
+
+// ReadSidecar reads a JSON sidecar, the returned heightmap has no data
+func ReadSidecar(r io.Reader) (*Heightmap, error) {
+	var hm Heightmap
+	err := json.NewDecoder(r).Decode(&hm)
+	if err != nil {
+		return nil, err
+	}
+	if hm.Width <= 0 || hm.Height <= 0 {
+		return nil, fmt.Errorf("heightmap: invalid sidecar size %dx%d", hm.Width, hm.Height)
+	}
+	if math.IsNaN(float64(hm.Min)) || math.IsNaN(float64(hm.Max)) {
+		return nil, errors.New("heightmap: invalid sidecar range")
+	}
+	return &hm, nil
+}
+
+// SidecarName returns the name of the JSON sidecar saved next to filename
+func SidecarName(filename string) string {
+	return filename + ".json"
+}
+
+// Save writes the heightmap to filename in the given format along with its JSON sidecar
+func Save(filename string, hm *Heightmap, format Format) error {
+	var write func(io.Writer, *Heightmap) error
+	switch format {
+	case PNG8:
+		write = WritePNG8
+	case PNG16:
+		write = WritePNG16
+	case PGM:
+		write = WritePGM
+	case RAW:
+		write = WriteRaw
+	default:
+		return fmt.Errorf("heightmap: unknown format %d", format)
+	}
+
+	err := writeFile(filename, hm, write)
+	if err != nil {
+		return err
+	}
+	return writeFile(SidecarName(filename), hm, WriteSidecar)
+}
+
+func writeFile(filename string, hm *Heightmap, write func(io.Writer, *Heightmap) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = write(file, hm)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads a heightmap, the format is picked by the file extension.
+// If a sidecar exists png and pgm values are mapped back to their original range.
+func Load(filename string) (*Heightmap, error) {
+	var sidecar *Heightmap
+	sidecarData, err := ioutil.ReadFile(SidecarName(filename))
+	if err == nil {
+		sidecar, err = ReadSidecar(bytes.NewReader(sidecarData))
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var hm *Heightmap
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png":
+		hm, err = ReadPNG(file)
+	case ".pgm":
+		hm, err = ReadPGM(file)
+	default:
+		if sidecar == nil {
+			return nil, fmt.Errorf("heightmap: %s needs a sidecar", filename)
+		}
+		return ReadRaw(file, bytes.NewReader(sidecarData))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if sidecar != nil {
+		if sidecar.Width != hm.Width || sidecar.Height != hm.Height {
+			return nil, fmt.Errorf("heightmap: %s is %dx%d but its sidecar is %dx%d",
+				filename, hm.Width, hm.Height, sidecar.Width, sidecar.Height)
+		}
+		hm.denormalize(sidecar.Min, sidecar.Max)
+		hm.Params = sidecar.Params
+	}
+	return hm, nil
+}
@@ -0,0 +1,152 @@
+package heightmap
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/noise"
+)
+
+func makeHeightmap(t *testing.T) *Heightmap {
+	spec := &noise.ModuleSpec{Type: "simplex", Noise: "fbm", Frequency: 0.05, Lacunarity: 2, Gain: 0.5, Octaves: 3}
+	module, err := spec.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := noise.Options{Module: module, Width: 23, Height: 17, OffsetX: 100, OffsetY: -50, Scale: 0.5}
+	result, err := noise.MakeNoise(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return FromResult(result, opts, spec)
+}
+
+func TestFromResultRecordsParams(t *testing.T) {
+	hm := makeHeightmap(t)
+	if hm.Width != 23 || hm.Height != 17 || len(hm.Data) != 23*17 {
+		t.Fatalf("heightmap is %dx%d with %d values", hm.Width, hm.Height, len(hm.Data))
+	}
+	p := hm.Params
+	if p == nil || p.Module == nil || p.Module.Type != "simplex" || p.OffsetX != 100 || p.OffsetY != -50 || p.Scale != 0.5 {
+		t.Errorf("params weren't recorded: %+v", p)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		ext    string
+		// steps is how many levels the format stores, 0 is exact
+		steps float32
+	}{
+		{"PNG8", PNG8, ".png", 255},
+		{"PNG16", PNG16, ".png", 65535},
+		{"PGM", PGM, ".pgm", 65535},
+		{"RAW", RAW, ".raw", 0},
+	}
+
+	hm := makeHeightmap(t)
+	dir := t.TempDir()
+	for _, tt := range tests {
+		filename := filepath.Join(dir, tt.name+tt.ext)
+		err := Save(filename, hm, tt.format)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		loaded, err := Load(filename)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if loaded.Width != hm.Width || loaded.Height != hm.Height {
+			t.Fatalf("%s: loaded %dx%d, want %dx%d", tt.name, loaded.Width, loaded.Height, hm.Width, hm.Height)
+		}
+		if loaded.Min != hm.Min || loaded.Max != hm.Max {
+			t.Errorf("%s: range %v..%v, want %v..%v", tt.name, loaded.Min, loaded.Max, hm.Min, hm.Max)
+		}
+		if loaded.Params == nil || loaded.Params.Module == nil || !reflect.DeepEqual(loaded.Params, hm.Params) {
+			t.Errorf("%s: params %+v, want %+v", tt.name, loaded.Params, hm.Params)
+		}
+
+		var tolerance float32
+		if tt.steps > 0 {
+			// half a level, plus a little for float32 rounding
+			tolerance = (hm.Max-hm.Min)/tt.steps*0.5 + 1e-6
+		}
+		for i := range hm.Data {
+			diff := loaded.Data[i] - hm.Data[i]
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > tolerance {
+				t.Fatalf("%s: value %d is %v, want %v within %v", tt.name, i, loaded.Data[i], hm.Data[i], tolerance)
+			}
+		}
+	}
+}
+
+func TestLoadRawNeedsSidecar(t *testing.T) {
+	hm := makeHeightmap(t)
+	filename := filepath.Join(t.TempDir(), "map.raw")
+	err := writeFile(filename, hm, WriteRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil {
+		t.Error("loaded a raw heightmap without a sidecar")
+	}
+}
+
+func TestReadPGMSkipsComments(t *testing.T) {
+	header := "P5\n# made by hand\n3 # width\n2\n#max\n255\n"
+	pixels := []byte{0, 51, 255, 102, '#', 10}
+	hm, err := ReadPGM(bytes.NewReader(append([]byte(header), pixels...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hm.Width != 3 || hm.Height != 2 {
+		t.Fatalf("read %dx%d, want 3x2", hm.Width, hm.Height)
+	}
+	// the pixels that look like a comment and a newline are still pixels
+	for i, p := range pixels {
+		if want := float32(p) / 255; hm.Data[i] != want {
+			t.Errorf("value %d is %v, want %v", i, hm.Data[i], want)
+		}
+	}
+
+	for _, bad := range []string{"P6\n3 2\n255\n", "P5\n3 x\n255\n", "P5\n# only a comment", "P5\n3 2\n70000\n"} {
+		if _, err := ReadPGM(bytes.NewReader([]byte(bad + "\x00\x00\x00\x00\x00\x00"))); err == nil {
+			t.Errorf("read pgm with header %q", bad)
+		}
+	}
+}
+
+func TestWriteChecksSize(t *testing.T) {
+	writers := []struct {
+		name  string
+		write func(io.Writer, *Heightmap) error
+	}{
+		{"PNG8", WritePNG8},
+		{"PNG16", WritePNG16},
+		{"PGM", WritePGM},
+		{"RAW", WriteRaw},
+	}
+	for _, w := range writers {
+		for _, n := range []int{5, 7} {
+			hm := &Heightmap{Width: 3, Height: 2, Max: 1, Data: make([]float32, n)}
+			var buf bytes.Buffer
+			if err := w.write(&buf, hm); err == nil {
+				t.Errorf("%s: wrote a 3x2 heightmap with %d values", w.name, n)
+			}
+		}
+		hm := &Heightmap{Width: 3, Height: 2, Max: 1, Data: make([]float32, 6)}
+		if err := w.write(&bytes.Buffer{}, hm); err != nil {
+			t.Errorf("%s: %v", w.name, err)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package noise
+
+import "sort"
+
+// Module is a node in a noise module graph, it can be evaluated at any point
+type Module interface {
+	Eval(x, y float32) float32
+}
+
+// Generators
+
+// Simplex generates fractal simplex noise of the given Type
+type Simplex struct {
+	Type       Type
+	Frequency  float32
+	Lacunarity float32
+	Gain       float32
+	Octaves    int
+}
+
+// Eval returns the fractal simplex noise at x, y
+func (m *Simplex) Eval(x, y float32) float32 {
+	switch m.Type {
+	case TURBULENCE:
+		return Turbulence(x, y, m.Frequency, m.Lacunarity, m.Gain, m.Octaves)
+	case DAMPED:
+		return DampedFbm2(x, y, m.Frequency, m.Lacunarity, m.Gain, m.Octaves)
+	}
+	return Fbm2(x, y, m.Frequency, m.Lacunarity, m.Gain, m.Octaves)
+}
+
+// Worley generates cellular noise, the distance to the nearest feature point.
+// With more than one octave it is layered into a fractal of the given Type,
+// DAMPED needs derivatives worley noise doesn't have so it is treated as FBM.
+type Worley struct {
+	Frequency  float32
+	Type       Type
+	Lacunarity float32
+	Gain       float32
+	Octaves    int
+}
+
+// Eval returns the worley noise at x, y
+func (m *Worley) Eval(x, y float32) float32 {
+	octaves := m.Octaves
+	if octaves < 1 {
+		octaves = 1
+	}
+	if m.Type == TURBULENCE {
+		return WorleyTurbulence(x, y, m.Frequency, m.Lacunarity, m.Gain, octaves)
+	}
+	return WorleyFbm2(x, y, m.Frequency, m.Lacunarity, m.Gain, octaves)
+}
+
+// Constant always returns the same value
+type Constant struct {
+	Value float32
+}
+
+// Eval returns the constant value
+func (m *Constant) Eval(x, y float32) float32 {
+	return m.Value
+}
+
+// Modifiers
+
+// ScaleBias multiplies the source by Scale and then adds Bias
+type ScaleBias struct {
+	Source Module
+	Scale  float32
+	Bias   float32
+}
+
+// Eval returns source * scale + bias
+func (m *ScaleBias) Eval(x, y float32) float32 {
+	return m.Source.Eval(x, y)*m.Scale + m.Bias
+}
+
+// Abs returns the absolute value of the source
+type Abs struct {
+	Source Module
+}
+
+// Eval returns the absolute value of the source
+func (m *Abs) Eval(x, y float32) float32 {
+	v := m.Source.Eval(x, y)
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Clamp limits the source to the range Min to Max
+type Clamp struct {
+	Source Module
+	Min    float32
+	Max    float32
+}
+
+// Eval returns the source clamped between min and max
+func (m *Clamp) Eval(x, y float32) float32 {
+	v := m.Source.Eval(x, y)
+	if v < m.Min {
+		return m.Min
+	} else if v > m.Max {
+		return m.Max
+	}
+	return v
+}
+
+// Invert negates the source
+type Invert struct {
+	Source Module
+}
+
+// Eval returns the negated source
+func (m *Invert) Eval(x, y float32) float32 {
+	return -m.Source.Eval(x, y)
+}
+
+// ControlPoint maps an input value of a curve to an output value
+type ControlPoint struct {
+	Input  float32 `json:"input"`
+	Output float32 `json:"output"`
+}
+
+// Curve remaps the source through a cubic curve passing through the control points,
+// at least 4 control points are needed
+type Curve struct {
+	Source Module
+	Points []ControlPoint
+}
+
+// NewCurve returns a curve with its control points sorted by input. Only the first
+// of several points with the same input is kept, Eval can't go between them.
+func NewCurve(source Module, points []ControlPoint) *Curve {
+	sorted := make([]ControlPoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Input < sorted[j].Input })
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p.Input != unique[len(unique)-1].Input {
+			unique = append(unique, p)
+		}
+	}
+	return &Curve{source, unique}
+}
+
+// Eval returns the source remapped through the curve
+func (m *Curve) Eval(x, y float32) float32 {
+	v := m.Source.Eval(x, y)
+	n := len(m.Points)
+	if n < 4 {
+		return v
+	}
+
+	index := 0
+	for index < n && v >= m.Points[index].Input {
+		index++
+	}
+
+	i0 := clampIndex(index-2, n)
+	i1 := clampIndex(index-1, n)
+	i2 := clampIndex(index, n)
+	i3 := clampIndex(index+1, n)
+
+	if i1 == i2 {
+		return m.Points[i1].Output
+	}
+
+	in0 := m.Points[i1].Input
+	in1 := m.Points[i2].Input
+	pct := (v - in0) / (in1 - in0)
+	return cubicInterp(m.Points[i0].Output, m.Points[i1].Output, m.Points[i2].Output, m.Points[i3].Output, pct)
+}
+
+// Terrace remaps the source into flat terraces separated by steep slopes,
+// at least 2 terrace points are needed
+type Terrace struct {
+	Source Module
+	Points []float32
+	Invert bool
+}
+
+// NewTerrace returns a terrace with its points sorted and repeated points removed
+func NewTerrace(source Module, points []float32, invert bool) *Terrace {
+	sorted := make([]float32, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	return &Terrace{source, unique, invert}
+}
+
+// Eval returns the source remapped into terraces
+func (m *Terrace) Eval(x, y float32) float32 {
+	v := m.Source.Eval(x, y)
+	n := len(m.Points)
+	if n < 2 {
+		return v
+	}
+
+	index := 0
+	for index < n && v >= m.Points[index] {
+		index++
+	}
+
+	i0 := clampIndex(index-1, n)
+	i1 := clampIndex(index, n)
+	if i0 == i1 {
+		return m.Points[i1]
+	}
+
+	v0 := m.Points[i0]
+	v1 := m.Points[i1]
+	pct := (v - v0) / (v1 - v0)
+	if m.Invert {
+		pct = 1 - pct
+		v0, v1 = v1, v0
+	}
+	pct *= pct
+	return lerp(v0, v1, pct)
+}
+
+// Combiners
+
+// Add adds the output of two modules
+type Add struct {
+	A, B Module
+}
+
+// Eval returns a + b
+func (m *Add) Eval(x, y float32) float32 {
+	return m.A.Eval(x, y) + m.B.Eval(x, y)
+}
+
+// Multiply multiplies the output of two modules
+type Multiply struct {
+	A, B Module
+}
+
+// Eval returns a * b
+func (m *Multiply) Eval(x, y float32) float32 {
+	return m.A.Eval(x, y) * m.B.Eval(x, y)
+}
+
+// Min returns the smaller output of two modules
+type Min struct {
+	A, B Module
+}
+
+// Eval returns the smaller of a and b
+func (m *Min) Eval(x, y float32) float32 {
+	a := m.A.Eval(x, y)
+	b := m.B.Eval(x, y)
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger output of two modules
+type Max struct {
+	A, B Module
+}
+
+// Eval returns the larger of a and b
+func (m *Max) Eval(x, y float32) float32 {
+	a := m.A.Eval(x, y)
+	b := m.B.Eval(x, y)
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Blend interpolates between A and B, a Control of -1 gives A and 1 gives B
+type Blend struct {
+	A, B    Module
+	Control Module
+}
+
+// Eval returns a and b blended by control
+func (m *Blend) Eval(x, y float32) float32 {
+	pct := (m.Control.Eval(x, y) + 1) / 2
+	return lerp(m.A.Eval(x, y), m.B.Eval(x, y), pct)
+}
+
+// Selectors
+
+// Select returns B where Control is between Lower and Upper and A elsewhere,
+// EdgeFalloff smooths the transition between the two
+type Select struct {
+	A, B        Module
+	Control     Module
+	Lower       float32
+	Upper       float32
+	EdgeFalloff float32
+}
+
+// Eval returns a or b depending on control
+func (m *Select) Eval(x, y float32) float32 {
+	c := m.Control.Eval(x, y)
+	falloff := m.EdgeFalloff
+	if half := (m.Upper - m.Lower) / 2; falloff > half {
+		falloff = half
+	}
+
+	if falloff > 0 {
+		switch {
+		case c < m.Lower-falloff:
+			return m.A.Eval(x, y)
+		case c < m.Lower+falloff:
+			lower := m.Lower - falloff
+			upper := m.Lower + falloff
+			pct := sCurve((c - lower) / (upper - lower))
+			return lerp(m.A.Eval(x, y), m.B.Eval(x, y), pct)
+		case c < m.Upper-falloff:
+			return m.B.Eval(x, y)
+		case c < m.Upper+falloff:
+			lower := m.Upper - falloff
+			upper := m.Upper + falloff
+			pct := sCurve((c - lower) / (upper - lower))
+			return lerp(m.B.Eval(x, y), m.A.Eval(x, y), pct)
+		default:
+			return m.A.Eval(x, y)
+		}
+	}
+
+	if c < m.Lower || c > m.Upper {
+		return m.A.Eval(x, y)
+	}
+	return m.B.Eval(x, y)
+}
+
+func lerp(a, b, pct float32) float32 {
+	return a + pct*(b-a)
+}
+
+func sCurve(pct float32) float32 {
+	return pct * pct * (3 - 2*pct)
+}
+
+func cubicInterp(n0, n1, n2, n3, pct float32) float32 {
+	p := (n3 - n2) - (n0 - n1)
+	q := (n0 - n1) - p
+	r := n2 - n0
+	return p*pct*pct*pct + q*pct*pct + r*pct + n1
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	} else if i > n-1 {
+		return n - 1
+	}
+	return i
+}
@@ -0,0 +1,170 @@
+package noise
+
+import (
+	"math"
+	"testing"
+)
+
+// ramp returns x, so modules can be checked at known source values
+type ramp struct{}
+
+func (ramp) Eval(x, y float32) float32 {
+	return x
+}
+
+func constant(v float32) Module {
+	return &Constant{Value: v}
+}
+
+func TestModifiers(t *testing.T) {
+	curve := NewCurve(ramp{}, []ControlPoint{{1, 10}, {-1, -10}, {0, 0}, {2, 40}, {0, 99}})
+	terrace := NewTerrace(ramp{}, []float32{1, 0, 1}, false)
+	inverted := NewTerrace(ramp{}, []float32{0, 1}, true)
+
+	tests := []struct {
+		name   string
+		module Module
+		x      float32
+		want   float32
+	}{
+		{"scalebias", &ScaleBias{ramp{}, 3, -1}, 2, 5},
+		{"abs of negative", &Abs{ramp{}}, -2.5, 2.5},
+		{"abs of positive", &Abs{ramp{}}, 2.5, 2.5},
+		{"clamp below", &Clamp{ramp{}, -1, 1}, -3, -1},
+		{"clamp above", &Clamp{ramp{}, -1, 1}, 3, 1},
+		{"clamp inside", &Clamp{ramp{}, -1, 1}, 0.5, 0.5},
+		{"invert", &Invert{ramp{}}, 4, -4},
+		// a curve passes through its control points and holds the end values outside them
+		{"curve at a point", curve, 1, 10},
+		{"curve at the first duplicate", curve, 0, 0},
+		{"curve below", curve, -5, -10},
+		{"curve above", curve, 5, 40},
+		// halfway between 0 and 1 with outputs -10, 0, 10, 40 around it:
+		// p = 30 - -10 = 40, q = -10 - 40 = -50, r = 20, so 40/8 - 50/4 + 20/2
+		{"curve between", curve, 0.5, 2.5},
+		{"terrace on a step", terrace, 1, 1},
+		{"terrace between", terrace, 0.5, 0.25},
+		{"terrace below", terrace, -1, 0},
+		{"inverted terrace between", inverted, 0.5, 0.75},
+	}
+	for _, tt := range tests {
+		if got := tt.module.Eval(tt.x, 0); math.Abs(float64(got-tt.want)) > 1e-5 {
+			t.Errorf("%s at %v is %v, want %v", tt.name, tt.x, got, tt.want)
+		}
+	}
+	if len(curve.Points) != 4 || len(terrace.Points) != 2 {
+		t.Errorf("duplicates weren't removed: %v, %v", curve.Points, terrace.Points)
+	}
+}
+
+func TestCombiners(t *testing.T) {
+	a, b := constant(3), constant(-5)
+	tests := []struct {
+		name   string
+		module Module
+		want   float32
+	}{
+		{"add", &Add{a, b}, -2},
+		{"multiply", &Multiply{a, b}, -15},
+		{"min", &Min{a, b}, -5},
+		{"max", &Max{a, b}, 3},
+		{"blend at -1", &Blend{a, b, constant(-1)}, 3},
+		{"blend at 0", &Blend{a, b, constant(0)}, -1},
+		{"blend at 1", &Blend{a, b, constant(1)}, -5},
+	}
+	for _, tt := range tests {
+		if got := tt.module.Eval(0, 0); got != tt.want {
+			t.Errorf("%s is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	a, b := constant(10), constant(20)
+	sharp := &Select{A: a, B: b, Control: ramp{}, Lower: 0, Upper: 1}
+	soft := &Select{A: a, B: b, Control: ramp{}, Lower: 0, Upper: 1, EdgeFalloff: 0.2}
+	// a falloff wider than half the range is cut down to half
+	wide := &Select{A: a, B: b, Control: ramp{}, Lower: 0, Upper: 1, EdgeFalloff: 5}
+
+	tests := []struct {
+		name   string
+		module Module
+		c      float32
+		want   float32
+	}{
+		{"sharp below", sharp, -0.01, 10},
+		{"sharp at lower", sharp, 0, 20},
+		{"sharp at upper", sharp, 1, 20},
+		{"sharp above", sharp, 1.01, 10},
+		{"soft well below", soft, -1, 10},
+		{"soft at the start of the falloff", soft, -0.2, 10},
+		{"soft at lower", soft, 0, 15},
+		{"soft at the end of the falloff", soft, 0.2, 20},
+		{"soft inside", soft, 0.5, 20},
+		// a quarter of the way through the upper falloff: sCurve(0.25) = 0.15625
+		{"soft going out", soft, 0.9, 18.4375},
+		{"soft at upper", soft, 1, 15},
+		{"soft past the falloff", soft, 1.2, 10},
+		{"wide in the middle", wide, 0.5, 20},
+		{"wide at lower", wide, 0, 15},
+	}
+	for _, tt := range tests {
+		if got := tt.module.Eval(tt.c, 0); math.Abs(float64(got-tt.want)) > 1e-4 {
+			t.Errorf("%s at %v is %v, want %v", tt.name, tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestParseModule(t *testing.T) {
+	module, err := ParseModule([]byte(`{"type": "add", "sources": [
+		{"type": "simplex", "noise": "fbm", "frequency": 0.01, "lacunarity": 2, "gain": 0.5, "octaves": 4},
+		{"type": "scalebias", "scale": 0.25, "bias": 1, "sources": [{"type": "worley", "frequency": 0.05}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Add{
+		&Simplex{FBM, 0.01, 2, 0.5, 4},
+		&ScaleBias{&Worley{Frequency: 0.05}, 0.25, 1},
+	}
+	for _, p := range [][2]float32{{0, 0}, {13.5, -7}, {100, 250}} {
+		if got, w := module.Eval(p[0], p[1]), want.Eval(p[0], p[1]); got != w {
+			t.Errorf("parsed module at %v is %v, want %v", p, got, w)
+		}
+	}
+
+	bad := []struct {
+		name, json string
+	}{
+		{"unknown type", `{"type": "wibble"}`},
+		{"unknown noise", `{"type": "simplex", "noise": "wibble"}`},
+		{"missing source", `{"type": "scalebias", "scale": 2}`},
+		{"too many sources", `{"type": "abs", "sources": [{"type": "constant"}, {"type": "constant"}]}`},
+		{"missing source of a combiner", `{"type": "add", "sources": [{"type": "constant"}]}`},
+		{"bad child", `{"type": "invert", "sources": [{"type": "wibble"}]}`},
+		{"missing control", `{"type": "blend", "sources": [{"type": "constant"}, {"type": "constant"}]}`},
+		{"too few curve points", `{"type": "curve", "sources": [{"type": "constant"}], "points": [{"input": 0, "output": 0}]}`},
+		{"duplicate curve points", `{"type": "curve", "sources": [{"type": "constant"}], "points": [
+			{"input": 0, "output": 0}, {"input": 1, "output": 1}, {"input": 1, "output": 2}, {"input": 2, "output": 3}]}`},
+		{"duplicate terrace steps", `{"type": "terrace", "sources": [{"type": "constant"}], "steps": [0, 0.5, 0.5]}`},
+		{"bad JSON", `{"type": "constant",`},
+		{"wrong field type", `{"type": "constant", "value": "high"}`},
+	}
+	for _, tt := range bad {
+		if _, err := ParseModule([]byte(tt.json)); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestDuplicatePointsStayFinite(t *testing.T) {
+	curve := NewCurve(ramp{}, []ControlPoint{{0, 0}, {0, 5}, {1, 1}, {1, 1}, {2, 0}, {3, 2}})
+	terrace := NewTerrace(ramp{}, []float32{0, 0, 1, 1}, false)
+	for x := float32(-1); x <= 4; x += 0.125 {
+		for _, m := range []Module{curve, terrace} {
+			if v := m.Eval(x, 0); math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				t.Fatalf("%T at %v is %v", m, x, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package noise
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// ModuleSpec is the JSON description of a module graph node.
+// Type selects the module, the other fields are only read by modules that use them.
+//
+//	{"type": "add", "sources": [
+//		{"type": "simplex", "noise": "fbm", "frequency": 0.01, "lacunarity": 2, "gain": 0.5, "octaves": 4},
+//		{"type": "scalebias", "scale": 0.25, "bias": 0, "sources": [{"type": "worley", "frequency": 0.05}]}
+//	]}
+type ModuleSpec struct {
+	Type    string       `json:"type"`
+	Sources []ModuleSpec `json:"sources,omitempty"`
+	Control *ModuleSpec  `json:"control,omitempty"`
+
+	Noise      string  `json:"noise,omitempty"`
+	Frequency  float32 `json:"frequency,omitempty"`
+	Lacunarity float32 `json:"lacunarity,omitempty"`
+	Gain       float32 `json:"gain,omitempty"`
+	Octaves    int     `json:"octaves,omitempty"`
+
+	Value       float32        `json:"value,omitempty"`
+	Scale       float32        `json:"scale,omitempty"`
+	Bias        float32        `json:"bias,omitempty"`
+	Min         float32        `json:"min,omitempty"`
+	Max         float32        `json:"max,omitempty"`
+	Points      []ControlPoint `json:"points,omitempty"`
+	Steps       []float32      `json:"steps,omitempty"`
+	Invert      bool           `json:"invert,omitempty"`
+	Lower       float32        `json:"lower,omitempty"`
+	Upper       float32        `json:"upper,omitempty"`
+	EdgeFalloff float32        `json:"edgeFalloff,omitempty"`
+}
+
+// ParseModule builds a module graph from its JSON description
+func ParseModule(data []byte) (Module, error) {
+	var spec ModuleSpec
+	err := json.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, err
+	}
+	return spec.Build()
+}
+
+// LoadModule builds a module graph from a JSON file
+func LoadModule(filename string) (Module, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseModule(data)
+}
+
+// Build turns the spec and all its children into a module graph
+func (spec *ModuleSpec) Build() (Module, error) {
+	switch spec.Type {
+	case "simplex":
+		noiseType, err := parseType(spec.Noise)
+		if err != nil {
+			return nil, err
+		}
+		return &Simplex{noiseType, spec.Frequency, spec.Lacunarity, spec.Gain, spec.Octaves}, nil
+	case "worley":
+		noiseType, err := parseType(spec.Noise)
+		if err != nil {
+			return nil, err
+		}
+		return &Worley{spec.Frequency, noiseType, spec.Lacunarity, spec.Gain, spec.Octaves}, nil
+	case "constant":
+		return &Constant{spec.Value}, nil
+	}
+
+	sources, err := spec.buildSources()
+	if err != nil {
+		return nil, err
+	}
+
+	switch spec.Type {
+	case "scalebias":
+		if err := spec.wantSources(sources, 1); err != nil {
+			return nil, err
+		}
+		return &ScaleBias{sources[0], spec.Scale, spec.Bias}, nil
+	case "abs":
+		if err := spec.wantSources(sources, 1); err != nil {
+			return nil, err
+		}
+		return &Abs{sources[0]}, nil
+	case "clamp":
+		if err := spec.wantSources(sources, 1); err != nil {
+			return nil, err
+		}
+		return &Clamp{sources[0], spec.Min, spec.Max}, nil
+	case "curve":
+		if err := spec.wantSources(sources, 1); err != nil {
+			return nil, err
+		}
+		if len(spec.Points) < 4 {
+			return nil, fmt.Errorf("noise: curve needs at least 4 points, got %d", len(spec.Points))
+		}
+		curve := NewCurve(sources[0], spec.Points)
+		if len(curve.Points) != len(spec.Points) {
+			return nil, errors.New("noise: curve has more than one point with the same input")
+		}
+		return curve, nil
+	case "terrace":
+		if err := spec.wantSources(sources, 1); err != nil {
+			return nil, err
+		}
+		if len(spec.Steps) < 2 {
+			return nil, fmt.Errorf("noise: terrace needs at least 2 steps, got %d", len(spec.Steps))
+		}
+		terrace := NewTerrace(sources[0], spec.Steps, spec.Invert)
+		if len(terrace.Points) != len(spec.Steps) {
+			return nil, errors.New("noise: terrace has the same step more than once")
+		}
+		return terrace, nil
+	case "invert":
+		if err := spec.wantSources(sources, 1); err != nil {
+			return nil, err
+		}
+		return &Invert{sources[0]}, nil
+	case "add":
+		if err := spec.wantSources(sources, 2); err != nil {
+			return nil, err
+		}
+		return &Add{sources[0], sources[1]}, nil
+	case "multiply":
+		if err := spec.wantSources(sources, 2); err != nil {
+			return nil, err
+		}
+		return &Multiply{sources[0], sources[1]}, nil
+	case "min":
+		if err := spec.wantSources(sources, 2); err != nil {
+			return nil, err
+		}
+		return &Min{sources[0], sources[1]}, nil
+	case "max":
+		if err := spec.wantSources(sources, 2); err != nil {
+			return nil, err
+		}
+		return &Max{sources[0], sources[1]}, nil
+	case "blend":
+		if err := spec.wantSources(sources, 2); err != nil {
+			return nil, err
+		}
+		control, err := spec.buildControl()
+		if err != nil {
+			return nil, err
+		}
+		return &Blend{sources[0], sources[1], control}, nil
+	case "select":
+		if err := spec.wantSources(sources, 2); err != nil {
+			return nil, err
+		}
+		control, err := spec.buildControl()
+		if err != nil {
+			return nil, err
+		}
+		return &Select{sources[0], sources[1], control, spec.Lower, spec.Upper, spec.EdgeFalloff}, nil
+	}
+
+	return nil, fmt.Errorf("noise: unknown module type %q", spec.Type)
+}
+
+func (spec *ModuleSpec) buildSources() ([]Module, error) {
+	sources := make([]Module, len(spec.Sources))
+	for i := range spec.Sources {
+		source, err := spec.Sources[i].Build()
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = source
+	}
+	return sources, nil
+}
+
+func (spec *ModuleSpec) buildControl() (Module, error) {
+	if spec.Control == nil {
+		return nil, fmt.Errorf("noise: %s module needs a control", spec.Type)
+	}
+	return spec.Control.Build()
+}
+
+func (spec *ModuleSpec) wantSources(sources []Module, n int) error {
+	if len(sources) != n {
+		return fmt.Errorf("noise: %s module needs %d sources, got %d", spec.Type, n, len(sources))
+	}
+	return nil
+}
+
+func parseType(name string) (Type, error) {
+	switch name {
+	case "", "fbm":
+		return FBM, nil
+	case "turbulence":
+		return TURBULENCE, nil
+	case "damped":
+		return DAMPED, nil
+	}
+	return FBM, fmt.Errorf("noise: unknown noise type %q", name)
+}
@@ -1,12 +1,15 @@
 package noise
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math"
 	"runtime"
 	"sync"
 )
 
-// Type indicates which noise MakeNoise will generate
+// Type indicates which fractal a Simplex module will generate
 type Type int
 
 const (
@@ -14,6 +17,8 @@ const (
 	FBM Type = iota
 	// TURBULENCE indicates Turbulent fractal
 	TURBULENCE
+	// DAMPED indicates Fractal Brownian Motion damped by its own slope
+	DAMPED
 )
 
 // Turbulence generates turbulant fractal noise
@@ -44,57 +49,133 @@ func Fbm2(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
 	return sum
 }
 
-// MakeNoise generates a 2d block of noise
-func MakeNoise(noiseType Type, frequency, lacunarity, gain float32, octaves, w, h int) (noise []float32, min, max float32) {
-	noise = make([]float32, w*h)
+// ErrBufferTooSmall is returned when a destination buffer can't hold the whole block
+var ErrBufferTooSmall = errors.New("noise: destination buffer too small")
 
-	min = float32(math.MaxFloat32)
-	max = float32(-math.MaxFloat32)
+// Options configures MakeNoise
+type Options struct {
+	// Module is the graph evaluated at every pixel
+	Module Module
+	// Width and Height are the size of the block in pixels
+	Width, Height int
+	// Workers is the number of goroutines to use, 0 uses one per CPU
+	Workers int
+	// OffsetX and OffsetY are the world space position of the top left pixel
+	OffsetX, OffsetY float32
+	// Scale is the world space size of one pixel, 0 means 1
+	Scale float32
+	// Float64 writes the noise to Result.Noise64 instead of Result.Noise
+	Float64 bool
+	// Dst and Dst64 are optional buffers the noise is written to instead of allocating
+	Dst   []float32
+	Dst64 []float64
+}
+
+// Result is a generated block of noise
+type Result struct {
+	Noise    []float32
+	Noise64  []float64
+	Min, Max float32
+}
+
+// MakeNoise evaluates a module graph over a 2d block, split across workers by rows.
+// It returns the context's error if the context is cancelled before the block is done.
+func MakeNoise(ctx context.Context, opts Options) (Result, error) {
+	var result Result
+	w, h := opts.Width, opts.Height
+	if opts.Module == nil {
+		return result, errors.New("noise: no module to evaluate")
+	}
+	if w <= 0 || h <= 0 {
+		return result, fmt.Errorf("noise: invalid size %dx%d", w, h)
+	}
 
-	numRoutines := runtime.NumCPU()
+	if opts.Float64 {
+		if opts.Dst64 == nil {
+			result.Noise64 = make([]float64, w*h)
+		} else if len(opts.Dst64) < w*h {
+			return result, ErrBufferTooSmall
+		} else {
+			result.Noise64 = opts.Dst64[:w*h]
+		}
+	} else {
+		if opts.Dst == nil {
+			result.Noise = make([]float32, w*h)
+		} else if len(opts.Dst) < w*h {
+			return result, ErrBufferTooSmall
+		} else {
+			result.Noise = opts.Dst[:w*h]
+		}
+	}
+
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	numRoutines := opts.Workers
+	if numRoutines <= 0 {
+		numRoutines = runtime.NumCPU()
+	}
+	if numRoutines > h {
+		numRoutines = h
+	}
+
+	mins := make([]float32, numRoutines)
+	maxs := make([]float32, numRoutines)
 	var wg sync.WaitGroup
 	wg.Add(numRoutines)
-	batchSize := len(noise) / numRoutines
-	minMaxChan := make(chan float32, numRoutines*2)
 
 	for i := 0; i < numRoutines; i++ {
 		go func(i int) {
 			defer wg.Done()
 			innerMin := float32(math.MaxFloat32)
 			innerMax := float32(-math.MaxFloat32)
-			start := i * batchSize
-			end := start + batchSize - 1
-			for j := start; j < end; j++ {
-				x := j % w
-				y := (j - x) / w
-				if noiseType == TURBULENCE {
-					noise[j] = Turbulence(float32(x), float32(y), frequency, lacunarity, gain, octaves)
-				} else if noiseType == FBM {
-					noise[j] = Fbm2(float32(x), float32(y), frequency, lacunarity, gain, octaves)
+			startRow := i * h / numRoutines
+			endRow := (i + 1) * h / numRoutines
+			for y := startRow; y < endRow; y++ {
+				if ctx.Err() != nil {
+					return
 				}
+				worldY := opts.OffsetY + float32(y)*scale
+				for x := 0; x < w; x++ {
+					v := opts.Module.Eval(opts.OffsetX+float32(x)*scale, worldY)
+					if opts.Float64 {
+						result.Noise64[y*w+x] = float64(v)
+					} else {
+						result.Noise[y*w+x] = v
+					}
 
-				if noise[j] < innerMin {
-					innerMin = noise[j]
-				} else if noise[j] > innerMax {
-					innerMax = noise[j]
+					if v < innerMin {
+						innerMin = v
+					}
+					if v > innerMax {
+						innerMax = v
+					}
 				}
 			}
-			minMaxChan <- innerMin
-			minMaxChan <- innerMax
+			mins[i] = innerMin
+			maxs[i] = innerMax
 		}(i)
 	}
 	wg.Wait()
-	close(minMaxChan)
 
-	for value := range minMaxChan {
-		if value < min {
-			min = value
-		} else if value > max {
-			max = value
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	result.Min = float32(math.MaxFloat32)
+	result.Max = float32(-math.MaxFloat32)
+	for i := range mins {
+		if mins[i] < result.Min {
+			result.Min = mins[i]
+		}
+		if maxs[i] > result.Max {
+			result.Max = maxs[i]
 		}
 	}
 
-	return noise, min, max
+	return result, nil
 }
 
 func fastFloor(x float32) int {
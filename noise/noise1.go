@@ -0,0 +1,58 @@
+package noise
+
+func grad1(hash uint8, x float32) float32 {
+	h := hash & 15
+	grad := float32(1 + h&7) // gradient value 1.0, 2.0, ..., 8.0
+	if h&8 != 0 {
+		grad = -grad // and a random sign for the gradient
+	}
+	return grad * x // multiply the gradient with the distance
+}
+
+// Snoise1 generates a 1D simplex noise in about -1 to 1, it repeats every 256 units
+func Snoise1(x float32) float32 {
+	i0 := fastFloor(x)
+	i1 := i0 + 1
+	x0 := x - float32(i0)
+	x1 := x0 - 1
+
+	t0 := 1 - x0*x0
+	t0 *= t0
+	n0 := t0 * t0 * grad1(perm[uint8(i0)], x0)
+
+	t1 := 1 - x1*x1
+	t1 *= t1
+	n1 := t1 * t1 * grad1(perm[uint8(i1)], x1)
+
+	// the maximum value of this noise is 8*(3/4)^4 = 2.53125,
+	// scale it to fit exactly within -1 to 1
+	return 0.395 * (n0 + n1)
+}
+
+// Fbm1 generates 1D fractal brownian motion noise
+func Fbm1(x, frequency, lacunarity, gain float32, octaves int) float32 {
+	sum := float32(0.0)
+	amplitude := float32(1.0)
+	for i := 0; i < octaves; i++ {
+		sum += Snoise1(x*frequency) * amplitude
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	return sum
+}
+
+// Turbulence1 generates 1D turbulent fractal noise
+func Turbulence1(x, frequency, lacunarity, gain float32, octaves int) float32 {
+	var sum float32
+	amplitude := float32(1.0)
+	for i := 0; i < octaves; i++ {
+		f := Snoise1(x*frequency) * amplitude
+		if f < 0 {
+			f = -1.0 * f
+		}
+		sum += f
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	return sum
+}
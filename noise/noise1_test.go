@@ -0,0 +1,19 @@
+package noise
+
+import "testing"
+
+func TestSnoise1RangeAndContinuity(t *testing.T) {
+	const step = 1e-3
+	prev := Snoise1(-300)
+	for x := float32(-300) + step; x < 300; x += step {
+		v := Snoise1(x)
+		if v < -1 || v > 1 {
+			t.Fatalf("Snoise1(%v) is %v, outside -1 to 1", x, v)
+		}
+		// the slope of the noise is a few units at most
+		if d := v - prev; d > 10*step || d < -10*step {
+			t.Fatalf("Snoise1 jumped from %v to %v at %v", prev, v, x)
+		}
+		prev = v
+	}
+}
@@ -0,0 +1,127 @@
+package noise
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// reference evaluates the module one pixel at a time on a single goroutine
+func reference(opts Options) ([]float32, float32, float32) {
+	scale := opts.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	values := make([]float32, opts.Width*opts.Height)
+	min := float32(math.MaxFloat32)
+	max := float32(-math.MaxFloat32)
+	for y := 0; y < opts.Height; y++ {
+		for x := 0; x < opts.Width; x++ {
+			v := opts.Module.Eval(opts.OffsetX+float32(x)*scale, opts.OffsetY+float32(y)*scale)
+			values[y*opts.Width+x] = v
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+	return values, min, max
+}
+
+func TestMakeNoiseMatchesReference(t *testing.T) {
+	module := &Simplex{Type: FBM, Frequency: 0.03, Lacunarity: 2, Gain: 0.5, Octaves: 3}
+	tests := []struct {
+		width, height, workers int
+	}{
+		{1, 1, 1},
+		{7, 5, 3},
+		{13, 11, 4},
+		{31, 3, 8},
+		{5, 2, 16},
+		{64, 17, 0},
+	}
+
+	for _, tt := range tests {
+		opts := Options{Module: module, Width: tt.width, Height: tt.height, Workers: tt.workers, OffsetX: 10.5, OffsetY: -3, Scale: 0.75}
+		want, wantMin, wantMax := reference(opts)
+
+		result, err := MakeNoise(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("%dx%d with %d workers: %v", tt.width, tt.height, tt.workers, err)
+		}
+		for i := range want {
+			if result.Noise[i] != want[i] {
+				t.Fatalf("%dx%d with %d workers: pixel %d is %v, want %v", tt.width, tt.height, tt.workers, i, result.Noise[i], want[i])
+			}
+		}
+		if result.Min != wantMin || result.Max != wantMax {
+			t.Errorf("%dx%d with %d workers: min, max = %v, %v, want %v, %v", tt.width, tt.height, tt.workers, result.Min, result.Max, wantMin, wantMax)
+		}
+	}
+}
+
+func TestMakeNoiseFillsEveryPixel(t *testing.T) {
+	// a constant module makes any pixel that was skipped stand out
+	dst := make([]float32, 9*7)
+	for i := range dst {
+		dst[i] = -1
+	}
+	result, err := MakeNoise(context.Background(), Options{Module: &Constant{Value: 2}, Width: 9, Height: 7, Workers: 5, Dst: dst})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range result.Noise {
+		if v != 2 {
+			t.Fatalf("pixel %d is %v, want 2", i, v)
+		}
+	}
+	if result.Min != 2 || result.Max != 2 {
+		t.Errorf("min, max = %v, %v, want 2, 2", result.Min, result.Max)
+	}
+}
+
+func TestMakeNoiseFloat64(t *testing.T) {
+	opts := Options{Module: &Worley{Frequency: 0.1}, Width: 15, Height: 9, Workers: 4, Float64: true}
+	want, wantMin, wantMax := reference(opts)
+
+	result, err := MakeNoise(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Noise != nil {
+		t.Error("float32 noise was written for a float64 block")
+	}
+	for i := range want {
+		if result.Noise64[i] != float64(want[i]) {
+			t.Fatalf("pixel %d is %v, want %v", i, result.Noise64[i], want[i])
+		}
+	}
+	if result.Min != wantMin || result.Max != wantMax {
+		t.Errorf("min, max = %v, %v, want %v, %v", result.Min, result.Max, wantMin, wantMax)
+	}
+}
+
+func TestMakeNoiseCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := MakeNoise(ctx, Options{Module: &Constant{}, Width: 100, Height: 100})
+	if err != ctx.Err() {
+		t.Errorf("got error %v, want %v", err, ctx.Err())
+	}
+}
+
+func TestMakeNoiseErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := MakeNoise(ctx, Options{Width: 4, Height: 4}); err == nil {
+		t.Error("no error without a module")
+	}
+	if _, err := MakeNoise(ctx, Options{Module: &Constant{}, Width: 0, Height: 4}); err == nil {
+		t.Error("no error for an empty block")
+	}
+	if _, err := MakeNoise(ctx, Options{Module: &Constant{}, Width: 4, Height: 4, Dst: make([]float32, 15)}); err != ErrBufferTooSmall {
+		t.Errorf("got error %v for a small buffer, want ErrBufferTooSmall", err)
+	}
+}
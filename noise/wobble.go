@@ -0,0 +1,84 @@
+package noise
+
+import "math"
+
+// period is how often Snoise1 repeats
+const period = 256
+
+// Wobble is a smooth random value that changes over time, for things like camera
+// shake, jitter and flicker. It can also be played as a procedural audio signal.
+type Wobble struct {
+	// Amplitude is the largest distance the value moves from zero
+	Amplitude float32
+	// Frequency is roughly how many times a second the value changes direction
+	Frequency float32
+	// Octaves adds finer detail, each octave is twice the frequency and half the
+	// amplitude of the last. Zero is treated as one.
+	Octaves int
+	// Seed picks a different curve, wobbles with different seeds move independently
+	Seed uint32
+
+	time float64
+}
+
+// offset returns where along the noise the seed's curve starts
+func (w *Wobble) offset() float64 {
+	// the golden ratio spreads consecutive seeds evenly around the period
+	const phi = 0.6180339887498949
+	o := float64(w.Seed) * phi
+	return (o - math.Floor(o)) * period
+}
+
+// At returns the value at t seconds, between -Amplitude and Amplitude
+func (w *Wobble) At(t float64) float32 {
+	octaves := w.Octaves
+	if octaves < 1 {
+		octaves = 1
+	}
+
+	var sum, total float32
+	amplitude := float32(1)
+	frequency := float64(w.Frequency)
+	for i := 0; i < octaves; i++ {
+		// wrap before converting to float32 so long running clocks keep their precision
+		x := math.Mod(t*frequency+w.offset(), period)
+		if x < 0 {
+			x += period
+		}
+		sum += Snoise1(float32(x)) * amplitude
+		total += amplitude
+		amplitude *= 0.5
+		frequency *= 2
+	}
+	return sum / total * w.Amplitude
+}
+
+// Update advances the wobble's own clock by elapsedTime seconds and returns the new value
+func (w *Wobble) Update(elapsedTime float64) float32 {
+	w.time += elapsedTime
+	return w.At(w.time)
+}
+
+// Value returns the value at the wobble's own clock
+func (w *Wobble) Value() float32 {
+	return w.At(w.time)
+}
+
+// PCM16 fills dst with signed 16 bit little endian mono samples starting at start
+// seconds and returns the time just after the last sample. Amplitude is in full
+// scale units, anything outside -1 to 1 is clipped.
+func (w *Wobble) PCM16(dst []byte, sampleRate int, start float64) float64 {
+	n := len(dst) / 2
+	for i := 0; i < n; i++ {
+		v := w.At(start + float64(i)/float64(sampleRate))
+		if v > 1 {
+			v = 1
+		} else if v < -1 {
+			v = -1
+		}
+		s := int16(v * math.MaxInt16)
+		dst[i*2] = byte(s)
+		dst[i*2+1] = byte(s >> 8)
+	}
+	return start + float64(n)/float64(sampleRate)
+}
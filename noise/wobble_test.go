@@ -0,0 +1,105 @@
+package noise
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestWobbleSeeds(t *testing.T) {
+	a := Wobble{Amplitude: 1, Frequency: 3, Octaves: 2, Seed: 1}
+	b := Wobble{Amplitude: 1, Frequency: 3, Octaves: 2, Seed: 2}
+	same := Wobble{Amplitude: 1, Frequency: 3, Octaves: 2, Seed: 1}
+	differ := 0
+	for i := 0; i < 1000; i++ {
+		ti := float64(i) * 0.01
+		if a.At(ti) != same.At(ti) {
+			t.Fatalf("the same seed gave %v and %v at %v", a.At(ti), same.At(ti), ti)
+		}
+		if a.At(ti) != b.At(ti) {
+			differ++
+		}
+	}
+	if differ < 900 {
+		t.Errorf("seeds 1 and 2 only differed at %d of 1000 times", differ)
+	}
+}
+
+func TestWobbleAmplitude(t *testing.T) {
+	for _, octaves := range []int{0, 1, 3, 6} {
+		w := Wobble{Amplitude: 7, Frequency: 2, Octaves: octaves, Seed: 9}
+		var largest float32
+		for i := 0; i < 100000; i++ {
+			v := w.At(float64(i) * 0.0037)
+			if v < -w.Amplitude || v > w.Amplitude {
+				t.Fatalf("%d octaves: value %v is outside the amplitude", octaves, v)
+			}
+			if v < 0 {
+				v = -v
+			}
+			if v > largest {
+				largest = v
+			}
+		}
+		// and it does use most of the range
+		if largest < w.Amplitude/2 {
+			t.Errorf("%d octaves: largest value is only %v", octaves, largest)
+		}
+	}
+}
+
+func TestWobbleUpdateMatchesAt(t *testing.T) {
+	w := Wobble{Amplitude: 2, Frequency: 1.5, Octaves: 3, Seed: 4}
+	at := w
+	var clock float64
+	for i := 0; i < 1000; i++ {
+		dt := 0.001 + float64(i%7)*0.003
+		clock += dt
+		if got, want := w.Update(dt), at.At(clock); got != want {
+			t.Fatalf("update %d gave %v, At(%v) gives %v", i, got, clock, want)
+		}
+		if w.Value() != at.At(clock) {
+			t.Fatalf("value after update %d doesn't match At", i)
+		}
+	}
+}
+
+func TestWobblePCM16(t *testing.T) {
+	const sampleRate = 8000
+	w := Wobble{Amplitude: 0.8, Frequency: 200, Octaves: 2, Seed: 3}
+	// an odd byte at the end is left alone
+	dst := make([]byte, 2*500+1)
+	dst[len(dst)-1] = 0xAB
+	start := 1.25
+	end := w.PCM16(dst, sampleRate, start)
+	if want := start + 500.0/sampleRate; end != want {
+		t.Errorf("returned %v, want %v", end, want)
+	}
+	for i := 0; i < 500; i++ {
+		got := int16(binary.LittleEndian.Uint16(dst[i*2:]))
+		want := int16(w.At(start+float64(i)/sampleRate) * math.MaxInt16)
+		if got != want {
+			t.Fatalf("sample %d is %d, want %d", i, got, want)
+		}
+	}
+	if dst[len(dst)-1] != 0xAB {
+		t.Error("the odd byte was written")
+	}
+
+	// too loud clips at full scale
+	loud := Wobble{Amplitude: 10, Frequency: 50, Seed: 3}
+	dst = make([]byte, 2*4000)
+	loud.PCM16(dst, sampleRate, 0)
+	var hitTop, hitBottom bool
+	for i := 0; i < len(dst); i += 2 {
+		s := int16(binary.LittleEndian.Uint16(dst[i:]))
+		if s < -math.MaxInt16 {
+			t.Fatalf("sample %d is %d, below -32767", i/2, s)
+		}
+		hitTop = hitTop || s == math.MaxInt16
+		hitBottom = hitBottom || s == -math.MaxInt16
+	}
+	if !hitTop || !hitBottom {
+		t.Errorf("loud wobble didn't clip at both ends: top %v, bottom %v", hitTop, hitBottom)
+	}
+}
@@ -0,0 +1,313 @@
+package world
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/sabith-th/games_with_go/noise"
+)
+
+// ErrClosed is returned when chunks are requested from a closed world
+var ErrClosed = errors.New("world: closed")
+
+var errDropped = errors.New("world: request dropped")
+
+// Coord is the integer position of a chunk, chunk 0,0 starts at world pixel 0,0
+type Coord struct {
+	X, Y int
+}
+
+// Chunk is a square block of noise
+type Chunk struct {
+	Coord    Coord
+	Noise    []float32
+	Min, Max float32
+}
+
+// Config configures a World
+type Config struct {
+	// Module is the graph the world is generated from
+	Module noise.Module
+	// ChunkSize is the width and height of a chunk in pixels
+	ChunkSize int
+	// Scale is the world space size of one pixel, 0 means 1
+	Scale float32
+	// Workers is the number of chunks generated at once, 0 uses one per CPU
+	Workers int
+	// MemoryBudget is the maximum number of bytes of cached chunk noise
+	MemoryBudget int
+}
+
+type pendingChunk struct {
+	done  chan struct{}
+	chunk *Chunk
+	err   error
+}
+
+// World is an unbounded noise map generated in chunks on demand
+type World struct {
+	module    noise.Module
+	chunkSize int
+	scale     float32
+	budget    int
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	requests chan Coord
+	wg       sync.WaitGroup
+
+	mutex   sync.Mutex
+	cache   map[Coord]*list.Element
+	lru     *list.List
+	pending map[Coord]*pendingChunk
+	used    int
+}
+
+// New creates a world and starts its worker pool
+func New(cfg Config) (*World, error) {
+	if cfg.Module == nil {
+		return nil, errors.New("world: no module to generate from")
+	}
+	if cfg.ChunkSize <= 0 {
+		return nil, fmt.Errorf("world: invalid chunk size %d", cfg.ChunkSize)
+	}
+
+	scale := cfg.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	world := &World{
+		module:    cfg.Module,
+		chunkSize: cfg.ChunkSize,
+		scale:     scale,
+		budget:    cfg.MemoryBudget,
+		ctx:       ctx,
+		cancel:    cancel,
+		requests:  make(chan Coord, workers*4),
+		cache:     make(map[Coord]*list.Element),
+		lru:       list.New(),
+		pending:   make(map[Coord]*pendingChunk),
+	}
+
+	world.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go world.worker()
+	}
+	return world, nil
+}
+
+// Close stops the worker pool, chunks still being waited on return ErrClosed
+func (world *World) Close() {
+	world.cancel()
+	world.wg.Wait()
+}
+
+func (world *World) worker() {
+	defer world.wg.Done()
+	for {
+		select {
+		case <-world.ctx.Done():
+			return
+		case coord := <-world.requests:
+			world.generate(coord)
+		}
+	}
+}
+
+func (world *World) generate(coord Coord) {
+	result, err := noise.MakeNoise(world.ctx, noise.Options{
+		Module:  world.module,
+		Width:   world.chunkSize,
+		Height:  world.chunkSize,
+		Workers: 1,
+		OffsetX: float32(coord.X*world.chunkSize) * world.scale,
+		OffsetY: float32(coord.Y*world.chunkSize) * world.scale,
+		Scale:   world.scale,
+	})
+
+	world.mutex.Lock()
+	defer world.mutex.Unlock()
+
+	p := world.pending[coord]
+	delete(world.pending, coord)
+	if err != nil {
+		p.err = err
+		close(p.done)
+		return
+	}
+
+	p.chunk = &Chunk{coord, result.Noise, result.Min, result.Max}
+	world.store(p.chunk)
+	close(p.done)
+}
+
+// store adds a chunk to the cache and evicts the least recently used chunks over budget,
+// the mutex must be held
+func (world *World) store(chunk *Chunk) {
+	world.cache[chunk.Coord] = world.lru.PushFront(chunk)
+	world.used += len(chunk.Noise) * 4
+
+	for world.used > world.budget && world.lru.Len() > 1 {
+		oldest := world.lru.Back()
+		old := world.lru.Remove(oldest).(*Chunk)
+		delete(world.cache, old.Coord)
+		world.used -= len(old.Noise) * 4
+	}
+}
+
+// lookup returns a cached chunk or the pending generation of it, queuing it if needed.
+// the mutex must be held
+func (world *World) lookup(coord Coord) (*Chunk, *pendingChunk, bool) {
+	if element, ok := world.cache[coord]; ok {
+		world.lru.MoveToFront(element)
+		return element.Value.(*Chunk), nil, false
+	}
+	if p, ok := world.pending[coord]; ok {
+		return nil, p, false
+	}
+	p := &pendingChunk{done: make(chan struct{})}
+	world.pending[coord] = p
+	return nil, p, true
+}
+
+// Request queues a chunk for generation without waiting for it.
+// It returns false if the queue is full and the request was dropped.
+func (world *World) Request(coord Coord) bool {
+	world.mutex.Lock()
+	_, p, isNew := world.lookup(coord)
+	if !isNew {
+		world.mutex.Unlock()
+		return true
+	}
+	world.mutex.Unlock()
+
+	select {
+	case world.requests <- coord:
+		return true
+	default:
+		world.mutex.Lock()
+		delete(world.pending, coord)
+		world.mutex.Unlock()
+		p.err = errDropped
+		close(p.done)
+		return false
+	}
+}
+
+// RequestArea queues every chunk overlapping the given world pixel rectangle
+func (world *World) RequestArea(minX, minY, maxX, maxY int) {
+	min := world.CoordAt(minX, minY)
+	max := world.CoordAt(maxX, maxY)
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			world.Request(Coord{x, y})
+		}
+	}
+}
+
+// Chunk returns the chunk at coord, waiting for it to be generated if it isn't cached
+func (world *World) Chunk(ctx context.Context, coord Coord) (*Chunk, error) {
+	for {
+		world.mutex.Lock()
+		chunk, p, isNew := world.lookup(coord)
+		world.mutex.Unlock()
+		if chunk != nil {
+			return chunk, nil
+		}
+
+		if isNew {
+			select {
+			case world.requests <- coord:
+			case <-world.ctx.Done():
+				return nil, ErrClosed
+			case <-ctx.Done():
+				world.mutex.Lock()
+				delete(world.pending, coord)
+				world.mutex.Unlock()
+				p.err = ctx.Err()
+				close(p.done)
+				return nil, ctx.Err()
+			}
+		}
+
+		select {
+		case <-p.done:
+		case <-world.ctx.Done():
+			return nil, ErrClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if p.chunk != nil {
+			return p.chunk, nil
+		}
+		if world.ctx.Err() != nil {
+			return nil, ErrClosed
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// a request dropped or cancelled by another caller, queue it again
+	}
+}
+
+// TryChunk returns the chunk at coord if it is cached, otherwise it queues it and returns false
+func (world *World) TryChunk(coord Coord) (*Chunk, bool) {
+	world.mutex.Lock()
+	element, ok := world.cache[coord]
+	if ok {
+		world.lru.MoveToFront(element)
+	}
+	world.mutex.Unlock()
+	if ok {
+		return element.Value.(*Chunk), true
+	}
+	world.Request(coord)
+	return nil, false
+}
+
+// CoordAt returns the coord of the chunk containing world pixel x, y
+func (world *World) CoordAt(x, y int) Coord {
+	return Coord{floorDiv(x, world.chunkSize), floorDiv(y, world.chunkSize)}
+}
+
+// Sample returns the noise at world pixel x, y, loading its chunk if needed.
+// If the world is closed the module is evaluated directly.
+func (world *World) Sample(x, y int) float32 {
+	coord := world.CoordAt(x, y)
+	localX := x - coord.X*world.chunkSize
+	localY := y - coord.Y*world.chunkSize
+	chunk, err := world.Chunk(context.Background(), coord)
+	if err != nil {
+		// same sum MakeNoise does so the values match the generated chunks
+		offsetX := float32(coord.X*world.chunkSize) * world.scale
+		offsetY := float32(coord.Y*world.chunkSize) * world.scale
+		return world.module.Eval(offsetX+float32(localX)*world.scale, offsetY+float32(localY)*world.scale)
+	}
+	return chunk.Noise[localY*world.chunkSize+localX]
+}
+
+// CachedBytes returns the number of bytes of noise currently cached
+func (world *World) CachedBytes() int {
+	world.mutex.Lock()
+	defer world.mutex.Unlock()
+	return world.used
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
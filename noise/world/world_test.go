@@ -0,0 +1,145 @@
+package world
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/noise"
+)
+
+// gated counts evaluations and blocks them until the gate is opened
+type gated struct {
+	evals   int64
+	entered chan struct{}
+	once    sync.Once
+	gate    chan struct{}
+}
+
+func (m *gated) Eval(x, y float32) float32 {
+	m.once.Do(func() { close(m.entered) })
+	<-m.gate
+	atomic.AddInt64(&m.evals, 1)
+	return x + y
+}
+
+func TestNewValidates(t *testing.T) {
+	if _, err := New(Config{ChunkSize: 8}); err == nil {
+		t.Error("no error without a module")
+	}
+	if _, err := New(Config{Module: &noise.Constant{}}); err == nil {
+		t.Error("no error for a zero chunk size")
+	}
+	if _, err := New(Config{Module: &noise.Constant{}, ChunkSize: -4}); err == nil {
+		t.Error("no error for a negative chunk size")
+	}
+}
+
+func TestPendingChunksAreShared(t *testing.T) {
+	module := &gated{entered: make(chan struct{}), gate: make(chan struct{})}
+	world, err := New(Config{Module: module, ChunkSize: 2, Workers: 1, MemoryBudget: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer world.Close()
+
+	coord := Coord{3, -2}
+	if !world.Request(coord) {
+		t.Fatal("first request was dropped")
+	}
+	<-module.entered
+
+	// the chunk is being generated, asking again mustn't queue it a second time
+	for i := 0; i < 10; i++ {
+		if !world.Request(coord) {
+			t.Fatal("repeat request was dropped")
+		}
+	}
+	world.mutex.Lock()
+	pending, queued := len(world.pending), len(world.requests)
+	world.mutex.Unlock()
+	if pending != 1 || queued != 0 {
+		t.Fatalf("%d pending and %d queued, want 1 and 0", pending, queued)
+	}
+
+	var wg sync.WaitGroup
+	chunks := make([]*Chunk, 5)
+	for i := range chunks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chunks[i], _ = world.Chunk(context.Background(), coord)
+		}(i)
+	}
+	close(module.gate)
+	wg.Wait()
+
+	for i, chunk := range chunks {
+		if chunk == nil || chunk != chunks[0] {
+			t.Fatalf("caller %d got a different chunk", i)
+		}
+	}
+	if evals := atomic.LoadInt64(&module.evals); evals != 4 {
+		t.Errorf("chunk was evaluated %d times, want once (4 pixels)", evals)
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	const chunkBytes = 4 * 4 * 4
+	world, err := New(Config{Module: &noise.Constant{Value: 1}, ChunkSize: 4, Workers: 2, MemoryBudget: 3 * chunkBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer world.Close()
+
+	ctx := context.Background()
+	a, b, c, d := Coord{0, 0}, Coord{1, 0}, Coord{0, 1}, Coord{-1, -1}
+	for _, coord := range []Coord{a, b, c} {
+		if _, err := world.Chunk(ctx, coord); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if used := world.CachedBytes(); used != 3*chunkBytes {
+		t.Fatalf("cached %d bytes, want %d", used, 3*chunkBytes)
+	}
+
+	// using a makes b the least recently used, so b goes when d arrives
+	if _, ok := world.TryChunk(a); !ok {
+		t.Fatal("a isn't cached")
+	}
+	if _, err := world.Chunk(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	if used := world.CachedBytes(); used != 3*chunkBytes {
+		t.Errorf("cached %d bytes after eviction, want %d", used, 3*chunkBytes)
+	}
+	world.mutex.Lock()
+	defer world.mutex.Unlock()
+	for _, coord := range []Coord{a, c, d} {
+		if _, ok := world.cache[coord]; !ok {
+			t.Errorf("%v was evicted", coord)
+		}
+	}
+	if _, ok := world.cache[b]; ok {
+		t.Error("least recently used chunk was kept")
+	}
+}
+
+func TestSampleAcrossChunks(t *testing.T) {
+	module := &noise.Simplex{Type: noise.FBM, Frequency: 0.1, Lacunarity: 2, Gain: 0.5, Octaves: 2}
+	world, err := New(Config{Module: module, ChunkSize: 5, MemoryBudget: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer world.Close()
+
+	for _, p := range [][2]int{{0, 0}, {4, 4}, {5, 5}, {-1, -1}, {-5, 3}, {-6, 12}} {
+		coord := world.CoordAt(p[0], p[1])
+		want := module.Eval(float32(coord.X*5)+float32(p[0]-coord.X*5), float32(coord.Y*5)+float32(p[1]-coord.Y*5))
+		if got := world.Sample(p[0], p[1]); got != want {
+			t.Errorf("sample at %v is %v, want %v", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package noise
+
+import "math"
+
+// Worley2 generates cellular noise, the distance from x, y to the nearest feature point.
+// Every integer cell holds one feature point jittered by the permutation table.
+func Worley2(x, y float32) float32 {
+	xi := fastFloor(x)
+	yi := fastFloor(y)
+
+	minDist := float32(math.MaxFloat32)
+	for j := yi - 1; j <= yi+1; j++ {
+		for i := xi - 1; i <= xi+1; i++ {
+			fx, fy := featurePoint(i, j)
+			xDiff := float32(i) + fx - x
+			yDiff := float32(j) + fy - y
+			dist := xDiff*xDiff + yDiff*yDiff
+			if dist < minDist {
+				minDist = dist
+			}
+		}
+	}
+	return float32(math.Sqrt(float64(minDist)))
+}
+
+// WorleyFbm2 layers octaves of worley noise, one octave is plain Worley2
+func WorleyFbm2(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
+	var sum float32
+	amplitude := float32(1.0)
+	for i := 0; i < octaves; i++ {
+		sum += Worley2(x*frequency, y*frequency) * amplitude
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	return sum
+}
+
+// WorleyTurbulence layers octaves of worley noise folded around the middle of its
+// range, which gives sharp ridges along the cell edges and around the feature points
+func WorleyTurbulence(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
+	var sum float32
+	amplitude := float32(1.0)
+	for i := 0; i < octaves; i++ {
+		f := Worley2(x*frequency, y*frequency)*2 - 1
+		if f < 0 {
+			f = -f
+		}
+		sum += f * amplitude
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+	return sum
+}
+
+// featurePoint returns the offset of the feature point within cell i, j
+func featurePoint(i, j int) (fx, fy float32) {
+	ii := uint8(i)
+	jj := uint8(j)
+	hx := perm[ii+perm[jj]]
+	hy := perm[hx+ii+1]
+	return float32(hx) / 255, float32(hy) / 255
+}
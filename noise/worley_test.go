@@ -0,0 +1,70 @@
+package noise
+
+import (
+	"math"
+	"testing"
+)
+
+// worleyPoints are spread over several cells, including negative ones
+var worleyPoints = [][2]float32{{0, 0}, {13.5, -7.25}, {-40, 3}, {0.999, 250.1}, {-3.7, -88.2}}
+
+func TestWorleyOctaves(t *testing.T) {
+	plain := &Worley{Frequency: 0.1}
+	for _, p := range worleyPoints {
+		if got, want := plain.Eval(p[0], p[1]), Worley2(p[0]*0.1, p[1]*0.1); got != want {
+			t.Errorf("single octave at %v is %v, want %v", p, got, want)
+		}
+	}
+
+	spec := ModuleSpec{Type: "worley", Noise: "turbulence", Frequency: 0.1, Lacunarity: 2, Gain: 0.5, Octaves: 3}
+	module, err := spec.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, ok := module.(*Worley)
+	if !ok || w.Type != TURBULENCE || w.Octaves != 3 || w.Lacunarity != 2 || w.Gain != 0.5 {
+		t.Fatalf("spec built %#v", module)
+	}
+	for _, p := range worleyPoints {
+		if got, want := w.Eval(p[0], p[1]), WorleyTurbulence(p[0], p[1], 0.1, 2, 0.5, 3); got != want {
+			t.Errorf("module at %v is %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestWorleyFractalsSingleOctave(t *testing.T) {
+	for _, p := range worleyPoints {
+		w := Worley2(p[0]*0.1, p[1]*0.1)
+		if got := WorleyFbm2(p[0], p[1], 0.1, 2, 0.5, 1); got != w {
+			t.Errorf("fbm at %v is %v, want %v", p, got, w)
+		}
+		folded := float32(math.Abs(float64(w*2 - 1)))
+		if got := WorleyTurbulence(p[0], p[1], 0.1, 2, 0.5, 1); got != folded {
+			t.Errorf("turbulence at %v is %v, want %v", p, got, folded)
+		}
+	}
+}
+
+func TestWorleyFractalRange(t *testing.T) {
+	// the nearest feature point is never further than the far corner of the
+	// point's own cell, so an octave of Worley2 is 0..√2 and folded it's 0..2√2-1
+	const gain = 0.5
+	fbmMax := float32(math.Sqrt2)
+	turbulenceMax := float32(2*math.Sqrt2 - 1)
+	for octaves := 1; octaves <= 5; octaves++ {
+		var amplitudes float32
+		for i, a := 0, float32(1); i < octaves; i, a = i+1, a*gain {
+			amplitudes += a
+		}
+		for y := float32(-50); y < 50; y += 1.3 {
+			for x := float32(-50); x < 50; x += 1.7 {
+				if v := WorleyFbm2(x, y, 0.2, 2, gain, octaves); v < 0 || v > fbmMax*amplitudes {
+					t.Fatalf("%d octave fbm at %v, %v is %v, want 0..%v", octaves, x, y, v, fbmMax*amplitudes)
+				}
+				if v := WorleyTurbulence(x, y, 0.2, 2, gain, octaves); v < 0 || v > turbulenceMax*amplitudes {
+					t.Fatalf("%d octave turbulence at %v, %v is %v, want 0..%v", octaves, x, y, v, turbulenceMax*amplitudes)
+				}
+			}
+		}
+	}
+}
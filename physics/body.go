@@ -0,0 +1,82 @@
+package physics
+
+import "github.com/sabith-th/games_with_go/vector3"
+
+// Integrator is how a world moves its bodies each step
+type Integrator int
+
+const (
+	// SemiImplicitEuler updates velocity first and then moves by the new velocity
+	SemiImplicitEuler Integrator = iota
+	// Verlet moves by the average of the old and new velocity, so steady forces like
+	// gravity give exactly the right path whatever the step size. Forces are only
+	// sampled once a step, so this isn't full velocity Verlet and the velocity is
+	// updated just as SemiImplicitEuler does it.
+	Verlet
+)
+
+// Body is a point mass or, if it has a Radius, a solid sphere
+type Body struct {
+	Position vector3.Vector3
+	Velocity vector3.Vector3
+	// Mass of 0 makes the body static, it never moves and nothing can push it
+	Mass float32
+	// Radius of 0 makes the body a point mass that doesn't collide
+	Radius float32
+	// Damping is the fraction of velocity lost per second, like air resistance
+	Damping float32
+	// Restitution is how bouncy the body is, 0 stops dead and 1 bounces back at full speed
+	Restitution float32
+	// IgnoreGravity stops the world's gravity pulling on the body
+	IgnoreGravity bool
+	// Data is for the caller, the world doesn't touch it
+	Data interface{}
+
+	force vector3.Vector3
+}
+
+// AddForce pushes the body during the next step, forces are cleared after each step
+func (b *Body) AddForce(f vector3.Vector3) {
+	b.force = b.force.Add(f)
+}
+
+// AddImpulse changes the body's velocity straight away as if it were hit
+func (b *Body) AddImpulse(impulse vector3.Vector3) {
+	if b.Mass == 0 {
+		return
+	}
+	b.Velocity = b.Velocity.Add(impulse.Mult(1 / b.Mass))
+}
+
+// Static reports whether the body never moves
+func (b *Body) Static() bool {
+	return b.Mass == 0
+}
+
+func (b *Body) inverseMass() float32 {
+	if b.Mass == 0 {
+		return 0
+	}
+	return 1 / b.Mass
+}
+
+func (b *Body) integrate(integrator Integrator, gravity vector3.Vector3, dt float32) {
+	acceleration := b.force.Mult(1 / b.Mass)
+	if !b.IgnoreGravity {
+		acceleration = acceleration.Add(gravity)
+	}
+	b.force = vector3.Vector3{}
+	damping := 1 - b.Damping*dt
+	if damping < 0 {
+		damping = 0
+	}
+
+	switch integrator {
+	case Verlet:
+		b.Position = b.Position.Add(b.Velocity.Mult(dt)).Add(acceleration.Mult(dt * dt / 2))
+		b.Velocity = b.Velocity.Add(acceleration.Mult(dt)).Mult(damping)
+	default:
+		b.Velocity = b.Velocity.Add(acceleration.Mult(dt)).Mult(damping)
+		b.Position = b.Position.Add(b.Velocity.Mult(dt))
+	}
+}
@@ -0,0 +1,190 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// DefaultTimeStep is the fixed step a world uses if its config doesn't set one
+const DefaultTimeStep = float32(1.0 / 120.0)
+
+// Config configures a World
+type Config struct {
+	Gravity    vector3.Vector3
+	Integrator Integrator
+	// TimeStep is the fixed time each step simulates, 0 uses DefaultTimeStep
+	TimeStep float32
+	// MaxSteps limits how many steps one call to Step takes so a long frame can't
+	// snowball, the rest of the time is dropped. 0 means 8.
+	MaxSteps int
+}
+
+// Contact is a collision found during a step. B is nil when A hit a plane.
+type Contact struct {
+	A, B  *Body
+	Plane geometry.Plane
+	// Normal points from B, or the plane, towards A
+	Normal vector3.Vector3
+	// Speed is how fast they were closing, useful for picking a sound
+	Speed float32
+}
+
+// World moves bodies in fixed steps. Bodies are processed in the order they were
+// added, so the same bodies and the same calls to Step always give the same result.
+type World struct {
+	gravity    vector3.Vector3
+	integrator Integrator
+	timeStep   float32
+	maxSteps   int
+
+	bodies      []*Body
+	planes      []geometry.Plane
+	accumulator float32
+
+	// OnContact is called for every collision, it can be nil
+	OnContact func(c Contact)
+}
+
+// NewWorld returns an empty world
+func NewWorld(cfg Config) *World {
+	timeStep := cfg.TimeStep
+	if timeStep <= 0 {
+		timeStep = DefaultTimeStep
+	}
+	maxSteps := cfg.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = 8
+	}
+	return &World{gravity: cfg.Gravity, integrator: cfg.Integrator, timeStep: timeStep, maxSteps: maxSteps}
+}
+
+// AddBody adds b to the world and returns it
+func (w *World) AddBody(b *Body) *Body {
+	w.bodies = append(w.bodies, b)
+	return b
+}
+
+// RemoveBody takes b out of the world
+func (w *World) RemoveBody(b *Body) {
+	for i, other := range w.bodies {
+		if other == b {
+			// keep the order so the simulation stays deterministic
+			w.bodies = append(w.bodies[:i], w.bodies[i+1:]...)
+			return
+		}
+	}
+}
+
+// Bodies returns the bodies in the world, don't modify the slice
+func (w *World) Bodies() []*Body {
+	return w.bodies
+}
+
+// AddPlane adds an immovable plane, spheres are kept on the side its normal faces
+func (w *World) AddPlane(p geometry.Plane) {
+	w.planes = append(w.planes, p)
+}
+
+// Step advances the world by elapsedTime in fixed steps and returns how many steps
+// it took. Time left over carries on to the next call.
+func (w *World) Step(elapsedTime float32) int {
+	w.accumulator += elapsedTime
+	steps := 0
+	for w.accumulator >= w.timeStep {
+		if steps == w.maxSteps {
+			w.accumulator = 0
+			break
+		}
+		w.step(w.timeStep)
+		w.accumulator -= w.timeStep
+		steps++
+	}
+	return steps
+}
+
+// Alpha returns how far between the last step and the next the world's clock is,
+// for drawing positions interpolated between steps
+func (w *World) Alpha() float32 {
+	return w.accumulator / w.timeStep
+}
+
+func (w *World) step(dt float32) {
+	for _, b := range w.bodies {
+		if !b.Static() {
+			b.integrate(w.integrator, w.gravity, dt)
+		}
+	}
+
+	for i, a := range w.bodies {
+		if a.Radius == 0 {
+			continue
+		}
+		for _, b := range w.bodies[i+1:] {
+			if b.Radius != 0 && !(a.Static() && b.Static()) {
+				w.collideSpheres(a, b)
+			}
+		}
+		if !a.Static() {
+			for _, p := range w.planes {
+				w.collidePlane(a, p)
+			}
+		}
+	}
+}
+
+func (w *World) collideSpheres(a, b *Body) {
+	diff := a.Position.Sub(b.Position)
+	radii := a.Radius + b.Radius
+	distSquared := diff.LengthSquared()
+	if distSquared >= radii*radii {
+		return
+	}
+
+	dist := float32(math.Sqrt(float64(distSquared)))
+	normal := vector3.Vector3{Y: 1}
+	if dist > 0 {
+		normal = diff.Mult(1 / dist)
+	}
+
+	// push them apart in proportion to how easy each is to move
+	invA, invB := a.inverseMass(), b.inverseMass()
+	correction := normal.Mult((radii - dist) / (invA + invB))
+	a.Position = a.Position.Add(correction.Mult(invA))
+	b.Position = b.Position.Sub(correction.Mult(invB))
+
+	closing := a.Velocity.Sub(b.Velocity).Dot(normal)
+	if closing >= 0 {
+		return
+	}
+	restitution := a.Restitution
+	if b.Restitution < restitution {
+		restitution = b.Restitution
+	}
+	impulse := normal.Mult(-(1 + restitution) * closing / (invA + invB))
+	a.Velocity = a.Velocity.Add(impulse.Mult(invA))
+	b.Velocity = b.Velocity.Sub(impulse.Mult(invB))
+
+	if w.OnContact != nil {
+		w.OnContact(Contact{A: a, B: b, Normal: normal, Speed: -closing})
+	}
+}
+
+func (w *World) collidePlane(a *Body, p geometry.Plane) {
+	dist := p.Distance(a.Position) - a.Radius
+	if dist >= 0 {
+		return
+	}
+	a.Position = a.Position.Sub(p.Normal.Mult(dist))
+
+	closing := a.Velocity.Dot(p.Normal)
+	if closing >= 0 {
+		return
+	}
+	a.Velocity = a.Velocity.Sub(p.Normal.Mult((1 + a.Restitution) * closing))
+
+	if w.OnContact != nil {
+		w.OnContact(Contact{A: a, Plane: p, Normal: p.Normal, Speed: -closing})
+	}
+}
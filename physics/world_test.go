@@ -0,0 +1,217 @@
+package physics
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// newTestWorld makes a box of bouncing balls that keep running into each other
+func newTestWorld(integrator Integrator) *World {
+	w := NewWorld(Config{Gravity: vector3.Vector3{Y: -10}, Integrator: integrator, MaxSteps: 1000})
+	w.AddPlane(geometry.Plane{Normal: vector3.Vector3{Y: 1}})
+	w.AddPlane(geometry.Plane{Normal: vector3.Vector3{X: 1}, D: 5})
+	w.AddPlane(geometry.Plane{Normal: vector3.Vector3{X: -1}, D: 5})
+	w.AddPlane(geometry.Plane{Normal: vector3.Vector3{Z: 1}, D: 5})
+	w.AddPlane(geometry.Plane{Normal: vector3.Vector3{Z: -1}, D: 5})
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 30; i++ {
+		w.AddBody(&Body{
+			Position:    vector3.Vector3{X: rng.Float32()*8 - 4, Y: 1 + rng.Float32()*8, Z: rng.Float32()*8 - 4},
+			Velocity:    vector3.Vector3{X: rng.Float32()*6 - 3, Z: rng.Float32()*6 - 3},
+			Mass:        0.5 + rng.Float32(),
+			Radius:      0.3 + rng.Float32()*0.3,
+			Damping:     0.1,
+			Restitution: 0.7,
+		})
+	}
+	w.AddBody(&Body{Position: vector3.Vector3{Y: 1}, Radius: 1})
+	return w
+}
+
+func sameBodies(t *testing.T, a, b *World) {
+	t.Helper()
+	for i := range a.Bodies() {
+		ba, bb := a.Bodies()[i], b.Bodies()[i]
+		if ba.Position != bb.Position || ba.Velocity != bb.Velocity {
+			t.Fatalf("body %d is at %v moving %v in one world and at %v moving %v in the other",
+				i, ba.Position, ba.Velocity, bb.Position, bb.Velocity)
+		}
+	}
+}
+
+func TestStepIsDeterministic(t *testing.T) {
+	const steps = 2000
+	for _, integrator := range []Integrator{SemiImplicitEuler, Verlet} {
+		a, b := newTestWorld(integrator), newTestWorld(integrator)
+		contactsA, contactsB := 0, 0
+		a.OnContact = func(Contact) { contactsA++ }
+		b.OnContact = func(Contact) { contactsB++ }
+
+		// a gets ragged frame times, b gets exactly one step per call
+		frames := rand.New(rand.NewSource(2))
+		taken := 0
+		for taken < steps {
+			taken += a.Step(frames.Float32() * 4 * DefaultTimeStep)
+		}
+		for i := 0; i < taken; i++ {
+			if n := b.Step(DefaultTimeStep); n != 1 {
+				t.Fatalf("a whole time step took %d steps", n)
+			}
+		}
+
+		sameBodies(t, a, b)
+		if contactsA == 0 || contactsA != contactsB {
+			t.Errorf("integrator %d: %d contacts in one world and %d in the other", integrator, contactsA, contactsB)
+		}
+	}
+}
+
+func TestStepWithForcesIsDeterministic(t *testing.T) {
+	a, b := newTestWorld(Verlet), newTestWorld(Verlet)
+	inputsA, inputsB := rand.New(rand.NewSource(3)), rand.New(rand.NewSource(3))
+	frames := rand.New(rand.NewSource(4))
+	for i := 0; i < 1000; i++ {
+		frame := frames.Float32() * 3 * DefaultTimeStep
+		for _, w := range []struct {
+			world  *World
+			inputs *rand.Rand
+		}{{a, inputsA}, {b, inputsB}} {
+			body := w.world.Bodies()[w.inputs.Intn(30)]
+			body.AddForce(vector3.Vector3{X: w.inputs.Float32()*20 - 10, Y: w.inputs.Float32() * 20})
+			w.world.Step(frame)
+		}
+	}
+	sameBodies(t, a, b)
+}
+
+func TestVerletFollowsGravityExactly(t *testing.T) {
+	w := NewWorld(Config{Gravity: vector3.Vector3{Y: -10}, Integrator: Verlet, TimeStep: 0.1})
+	b := w.AddBody(&Body{Velocity: vector3.Vector3{X: 1, Y: 10}, Mass: 1})
+	for i := 0; i < 8; i++ {
+		w.Step(0.1)
+	}
+	// y = v t - g t^2 / 2 at t = 0.8
+	if y := b.Position.Y; y < 4.8-1e-4 || y > 4.8+1e-4 {
+		t.Errorf("height after 0.8s is %v, want 4.8", y)
+	}
+}
+
+func TestStepCarriesLeftoverTime(t *testing.T) {
+	w := NewWorld(Config{TimeStep: 0.25, MaxSteps: 3})
+	tests := []struct {
+		elapsed float32
+		steps   int
+	}{
+		{0.125, 0},
+		{0.25, 1},
+		{0.375, 2},
+		// too long a frame takes MaxSteps and drops the rest
+		{5, 3},
+		{0.125, 0},
+	}
+	for i, tt := range tests {
+		if steps := w.Step(tt.elapsed); steps != tt.steps {
+			t.Errorf("call %d took %d steps, want %d", i, steps, tt.steps)
+		}
+	}
+}
+
+// collide steps a gravity free world once with two spheres moving towards each other
+func collide(a, b *Body) []Contact {
+	w := NewWorld(Config{TimeStep: 0.01})
+	var contacts []Contact
+	w.OnContact = func(c Contact) { contacts = append(contacts, c) }
+	w.AddBody(a)
+	w.AddBody(b)
+	w.Step(0.01)
+	return contacts
+}
+
+func nearVec(a, b vector3.Vector3) bool {
+	return a.Sub(b).Length() < 1e-4
+}
+
+func TestElasticCollisionSwapsVelocities(t *testing.T) {
+	a := &Body{Position: vector3.Vector3{X: -0.95}, Velocity: vector3.Vector3{X: 2}, Mass: 1, Radius: 1, Restitution: 1}
+	b := &Body{Position: vector3.Vector3{X: 0.95}, Velocity: vector3.Vector3{X: -1}, Mass: 1, Radius: 1, Restitution: 1}
+	contacts := collide(a, b)
+	if len(contacts) != 1 {
+		t.Fatalf("%d contacts, want 1", len(contacts))
+	}
+	// equal masses head on swap velocities
+	if !nearVec(a.Velocity, vector3.Vector3{X: -1}) || !nearVec(b.Velocity, vector3.Vector3{X: 2}) {
+		t.Errorf("velocities after the collision are %v and %v", a.Velocity, b.Velocity)
+	}
+	if c := contacts[0]; c.A != a || c.B != b || !nearVec(c.Normal, vector3.Vector3{X: -1}) || abs(c.Speed-3) > 1e-4 {
+		t.Errorf("contact is %+v, want a closing speed of 3 along -x", c)
+	}
+	// and they have been pushed apart
+	if gap := b.Position.X - a.Position.X; gap < 2-1e-4 {
+		t.Errorf("spheres still overlap, centers are %v apart", gap)
+	}
+}
+
+func TestInelasticCollisionStops(t *testing.T) {
+	a := &Body{Position: vector3.Vector3{X: -0.95}, Velocity: vector3.Vector3{X: 3}, Mass: 2, Radius: 1}
+	b := &Body{Position: vector3.Vector3{X: 0.95}, Velocity: vector3.Vector3{X: -1}, Mass: 1, Radius: 1, Restitution: 1}
+	collide(a, b)
+	// the least bouncy body wins, so nothing bounces and they move on together
+	if closing := a.Velocity.Sub(b.Velocity).Dot(vector3.Vector3{X: 1}); abs(closing) > 1e-4 {
+		t.Errorf("still closing at %v after a dead collision", closing)
+	}
+	// momentum is kept: 2*3 + 1*-1 = 5 shared over a mass of 3
+	if !nearVec(a.Velocity, vector3.Vector3{X: 5.0 / 3}) {
+		t.Errorf("velocity after the collision is %v, want 5/3", a.Velocity)
+	}
+}
+
+func TestStaticBodyDoesntMove(t *testing.T) {
+	wall := &Body{Position: vector3.Vector3{X: 1}, Radius: 1, Restitution: 1}
+	ball := &Body{Position: vector3.Vector3{X: -0.9}, Velocity: vector3.Vector3{X: 4}, Mass: 1, Radius: 1, Restitution: 0.5}
+	contacts := collide(ball, wall)
+	if len(contacts) != 1 {
+		t.Fatalf("%d contacts, want 1", len(contacts))
+	}
+	if wall.Position != (vector3.Vector3{X: 1}) || wall.Velocity != (vector3.Vector3{}) {
+		t.Errorf("static body moved to %v with velocity %v", wall.Position, wall.Velocity)
+	}
+	if !nearVec(ball.Velocity, vector3.Vector3{X: -2}) || ball.Position.X > -1+1e-4 {
+		t.Errorf("ball is at %v moving %v, want it pushed out and bouncing back at half speed", ball.Position, ball.Velocity)
+	}
+}
+
+func TestPlaneBounce(t *testing.T) {
+	for _, restitution := range []float32{0, 0.5, 1} {
+		w := NewWorld(Config{TimeStep: 0.01})
+		w.AddPlane(geometry.Plane{Normal: vector3.Vector3{Y: 1}})
+		var contacts []Contact
+		w.OnContact = func(c Contact) { contacts = append(contacts, c) }
+		ball := w.AddBody(&Body{Position: vector3.Vector3{Y: 1.02}, Velocity: vector3.Vector3{X: 1, Y: -4}, Mass: 1, Radius: 1, Restitution: restitution})
+		w.Step(0.01)
+
+		if len(contacts) != 1 {
+			t.Fatalf("restitution %v: %d contacts, want 1", restitution, len(contacts))
+		}
+		if c := contacts[0]; c.B != nil || c.A != ball || abs(c.Speed-4) > 1e-4 {
+			t.Errorf("restitution %v: contact is %+v, want a closing speed of 4", restitution, c)
+		}
+		// only the speed into the plane is scaled, sliding along it is kept
+		if !nearVec(ball.Velocity, vector3.Vector3{X: 1, Y: 4 * restitution}) {
+			t.Errorf("restitution %v: velocity off the plane is %v", restitution, ball.Velocity)
+		}
+		if ball.Position.Y < 1-1e-4 {
+			t.Errorf("restitution %v: ball is below the plane at %v", restitution, ball.Position.Y)
+		}
+	}
+}
+
+func abs(a float32) float32 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/sabith-th/games_with_go/pong/game"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const controlsFile = "controls.json"
+
+// binding is the keys for one player, keys are SDL scancode names like "W", "Up" or "Space"
+type binding struct {
+	Up    []string `json:"up"`
+	Down  []string `json:"down"`
+	Serve []string `json:"serve"`
+}
+
+// controlsConfig is what controls.json holds
+//
+//	{
+//	  "players": [
+//	    {"up": ["W"], "down": ["S"], "serve": ["Space"]},
+//	    {"up": ["Up"], "down": ["Down"], "serve": ["Return"]}
+//	  ],
+//	  "deadzone": 8000
+//	}
+type controlsConfig struct {
+	Players [2]binding `json:"players"`
+	// Deadzone is how far a controller stick has to move, out of 32767, before the paddle does
+	Deadzone int16 `json:"deadzone"`
+}
+
+// defaultControls returns the bindings used when there's no controls.json, it makes
+// new slices every time so decoding over them can't change the defaults
+func defaultControls() controlsConfig {
+	return controlsConfig{
+		Players: [2]binding{
+			{Up: []string{"W"}, Down: []string{"S"}, Serve: []string{"Space"}},
+			{Up: []string{"Up"}, Down: []string{"Down"}, Serve: []string{"Return"}},
+		},
+		Deadzone: 8000,
+	}
+}
+
+// keys is a binding with the names looked up
+type keys struct {
+	up, down, serve []sdl.Scancode
+}
+
+func scancodes(names []string) ([]sdl.Scancode, error) {
+	codes := make([]sdl.Scancode, len(names))
+	for i, name := range names {
+		codes[i] = sdl.GetScancodeFromName(name)
+		if codes[i] == sdl.SCANCODE_UNKNOWN {
+			return nil, fmt.Errorf("pong: unknown key %q", name)
+		}
+	}
+	return codes, nil
+}
+
+func (b binding) keys() (keys, error) {
+	var k keys
+	var err error
+	if k.up, err = scancodes(b.Up); err != nil {
+		return k, err
+	}
+	if k.down, err = scancodes(b.Down); err != nil {
+		return k, err
+	}
+	k.serve, err = scancodes(b.Serve)
+	return k, err
+}
+
+// controls turns the keyboard and any game controllers into game inputs
+type controls struct {
+	keys        [2]keys
+	deadzone    int16
+	controllers []*sdl.GameController
+}
+
+// loadControls reads the key bindings from controls.json, the defaults are used if
+// there isn't one or it can't be used. It only fails if the defaults don't work either.
+func loadControls() (*controls, error) {
+	// anything controls.json leaves out keeps its default
+	cfg := defaultControls()
+	data, err := ioutil.ReadFile(controlsFile)
+	if err == nil {
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Println(err)
+		cfg = defaultControls()
+	}
+
+	c, err := newControls(cfg)
+	if err != nil {
+		fmt.Println(err)
+		c, err = newControls(defaultControls())
+	}
+	return c, err
+}
+
+func newControls(cfg controlsConfig) (*controls, error) {
+	c := &controls{deadzone: cfg.Deadzone}
+	for i, b := range cfg.Players {
+		k, err := b.keys()
+		if err != nil {
+			return nil, err
+		}
+		c.keys[i] = k
+	}
+	return c, nil
+}
+
+// openControllers opens every connected game controller, the first one belongs to
+// player one and the second to player two
+func (c *controls) openControllers() {
+	c.closeControllers()
+	for i := 0; i < sdl.NumJoysticks(); i++ {
+		if sdl.IsGameController(i) {
+			if controller := sdl.GameControllerOpen(i); controller != nil {
+				c.controllers = append(c.controllers, controller)
+			}
+		}
+	}
+}
+
+func (c *controls) closeControllers() {
+	for _, controller := range c.controllers {
+		controller.Close()
+	}
+	c.controllers = nil
+}
+
+func anyPressed(keyState []uint8, codes []sdl.Scancode) bool {
+	for _, code := range codes {
+		if keyState[code] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func keyboardInput(keyState []uint8, k keys) game.Input {
+	var input game.Input
+	if anyPressed(keyState, k.up) {
+		input.Move--
+	}
+	if anyPressed(keyState, k.down) {
+		input.Move++
+	}
+	input.Serve = anyPressed(keyState, k.serve)
+	return input
+}
+
+func (c *controls) controllerInput(controller *sdl.GameController) game.Input {
+	var input game.Input
+	if !controller.Attached() {
+		return input
+	}
+
+	axis := controller.Axis(sdl.CONTROLLER_AXIS_LEFTY)
+	if axis > c.deadzone || axis < -c.deadzone {
+		input.Move = float32(axis) / 32767
+	}
+	if controller.Button(sdl.CONTROLLER_BUTTON_DPAD_UP) != 0 {
+		input.Move = -1
+	}
+	if controller.Button(sdl.CONTROLLER_BUTTON_DPAD_DOWN) != 0 {
+		input.Move = 1
+	}
+	input.Serve = controller.Button(sdl.CONTROLLER_BUTTON_A) != 0 || controller.Button(sdl.CONTROLLER_BUTTON_START) != 0
+	return input
+}
+
+// serveName returns the name of player one's serve key for messages
+func (c *controls) serveName() string {
+	if len(c.keys[0].serve) == 0 {
+		return "SERVE"
+	}
+	return strings.ToUpper(sdl.GetScancodeName(c.keys[0].serve[0]))
+}
+
+// combine adds a and b's movement together and serves if either of them does
+func combine(a, b game.Input) game.Input {
+	return game.Input{Move: a.Move + b.Move, Serve: a.Serve || b.Serve}
+}
+
+// input returns what player, counting from 0, is doing. A lone human player
+// can use either player's keys and the first controller.
+func (c *controls) input(keyState []uint8, player int, alone bool) game.Input {
+	input := keyboardInput(keyState, c.keys[player])
+	if alone {
+		input = combine(input, keyboardInput(keyState, c.keys[1-player]))
+	}
+	if player < len(c.controllers) {
+		input = combine(input, c.controllerInput(c.controllers[player]))
+	}
+	return input
+}
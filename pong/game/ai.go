@@ -0,0 +1,107 @@
+package game
+
+import (
+	"math/rand"
+
+	"github.com/sabith-th/games_with_go/noise"
+)
+
+// Difficulty is how well an AI plays
+type Difficulty struct {
+	Name string
+	// ReactionTime is how many seconds the AI takes to notice the ball has changed direction
+	ReactionTime float32
+	// Error is the furthest in pixels the AI's guess of where the ball will arrive can be off
+	Error float32
+	// Wobble is how far in pixels the AI's aim drifts while it tracks the ball
+	Wobble float32
+	// MistakeChance is the chance of misjudging a shot completely and missing it
+	MistakeChance float32
+	// SpeedScale is how much of the paddle's speed the AI uses, from 0 to 1
+	SpeedScale float32
+}
+
+// Easy, Normal and Hard are the difficulty presets
+var (
+	Easy   = Difficulty{Name: "EASY", ReactionTime: 0.3, Error: 45, Wobble: 20, MistakeChance: 0.12, SpeedScale: 0.75}
+	Normal = Difficulty{Name: "NORMAL", ReactionTime: 0.2, Error: 30, Wobble: 10, MistakeChance: 0.05, SpeedScale: 0.9}
+	Hard   = Difficulty{Name: "HARD", ReactionTime: 0.12, Error: 15, Wobble: 5, MistakeChance: 0.02, SpeedScale: 1}
+)
+
+// Difficulties are the presets from easiest to hardest
+var Difficulties = []Difficulty{Easy, Normal, Hard}
+
+// AI plays one side of a game. It works out where the ball will arrive and moves
+// there like a player would, so it can be beaten.
+type AI struct {
+	Side       Side
+	Difficulty Difficulty
+
+	rng     *rand.Rand
+	wobble  noise.Wobble
+	heading bool
+	waiting float32
+	offset  float32
+	// target is where the AI is moving its paddle to, once it has picked somewhere
+	target    float32
+	hasTarget bool
+}
+
+// NewAI returns an AI for side, the same seed makes the same choices
+func NewAI(side Side, difficulty Difficulty, seed int64) *AI {
+	return &AI{
+		Side:       side,
+		Difficulty: difficulty,
+		rng:        rand.New(rand.NewSource(seed)),
+		wobble:     noise.Wobble{Amplitude: difficulty.Wobble, Frequency: 0.5, Octaves: 2, Seed: uint32(seed)},
+	}
+}
+
+// Input returns how the AI moves its paddle this step. It never serves, that is
+// left to whoever is running the game.
+func (ai *AI) Input(g *Game, elapsedTime float32) Input {
+	paddle := &g.Paddles[ai.Side]
+	aim := ai.wobble.Update(float64(elapsedTime))
+	if !ai.hasTarget {
+		ai.target = paddle.Pos.Y
+		ai.hasTarget = true
+	}
+
+	heading := g.Heading(ai.Side)
+	if heading != ai.heading {
+		ai.heading = heading
+		ai.waiting = ai.Difficulty.ReactionTime
+		if heading {
+			ai.offset = (ai.rng.Float32()*2 - 1) * ai.Difficulty.Error
+			if ai.rng.Float32() < ai.Difficulty.MistakeChance {
+				// aim far enough off that the ball goes past the paddle
+				miss := paddle.Height/2 + g.Ball.Radius + 10 + ai.rng.Float32()*paddle.Height/2
+				if ai.rng.Intn(2) == 0 {
+					miss = -miss
+				}
+				ai.offset = miss
+			}
+		}
+	}
+
+	if ai.waiting > 0 {
+		// still reacting, keep going where we were going
+		ai.waiting -= elapsedTime
+	} else if y, _, ok := g.PredictBall(ai.Side); ok {
+		ai.target = y + ai.offset + aim
+	} else {
+		ai.target = g.Center().Y + aim
+	}
+
+	var input Input
+	if elapsedTime <= 0 || paddle.Speed <= 0 {
+		return input
+	}
+	input.Move = (ai.target - paddle.Pos.Y) / (paddle.Speed * elapsedTime)
+	if input.Move > ai.Difficulty.SpeedScale {
+		input.Move = ai.Difficulty.SpeedScale
+	} else if input.Move < -ai.Difficulty.SpeedScale {
+		input.Move = -ai.Difficulty.SpeedScale
+	}
+	return input
+}
@@ -0,0 +1,159 @@
+package game
+
+import "testing"
+
+const aiTimeStep = float32(1) / 120
+
+// player returns the input for one side of a game each step
+type player func(g *Game, side Side, elapsedTime float32) Input
+
+// still never moves its paddle
+func still(g *Game, side Side, elapsedTime float32) Input {
+	return Input{}
+}
+
+// tracker chases the ball's height at full speed without predicting anything
+func tracker(g *Game, side Side, elapsedTime float32) Input {
+	diff := g.Ball.Pos.Y - g.Paddles[side].Pos.Y
+	if diff > 5 {
+		return Input{Move: 1}
+	} else if diff < -5 {
+		return Input{Move: -1}
+	}
+	return Input{}
+}
+
+// aiPlayer returns a player backed by an AI, made when it first knows its side
+func aiPlayer(difficulty Difficulty, seed int64) player {
+	var ai *AI
+	return func(g *Game, side Side, elapsedTime float32) Input {
+		if ai == nil {
+			ai = NewAI(side, difficulty, seed)
+		}
+		return ai.Input(g, elapsedTime)
+	}
+}
+
+// match is how a match went, for comparing two runs
+type match struct {
+	events  []Event
+	ball    Ball
+	paddles [2]Paddle
+	winner  Side
+}
+
+// playMatch plays left against right, left serving, and returns how it went.
+// ok is false if nobody had won after half an hour.
+func playMatch(left, right player, seed int64) (m match, ok bool) {
+	cfg := DefaultConfig()
+	cfg.Seed = seed
+	g := New(cfg)
+	for t := float32(0); t < 30*60; t += aiTimeStep {
+		inputs := [2]Input{left(g, Left, aiTimeStep), right(g, Right, aiTimeStep)}
+		inputs[Left].Serve = true
+		m.events = append(m.events, g.Step(inputs, aiTimeStep)...)
+		if g.Phase == MatchOver {
+			ok = true
+			break
+		}
+	}
+	m.ball, m.paddles, m.winner = g.Ball, g.Paddles, g.Winner
+	return m, ok
+}
+
+// winRate plays an AI against opponent, swapping sides every match, and returns
+// the share the AI won
+func winRate(t *testing.T, difficulty Difficulty, opponent func(m int) player, matches int) float32 {
+	t.Helper()
+	wins := 0
+	for m := 0; m < matches; m++ {
+		aiSide := Side(m % 2)
+		var players [2]player
+		players[aiSide] = aiPlayer(difficulty, int64(2*m))
+		players[aiSide.Other()] = opponent(m)
+		result, ok := playMatch(players[Left], players[Right], int64(m))
+		if !ok {
+			t.Fatalf("%s match %d never finished", difficulty.Name, m)
+		}
+		if result.winner == aiSide {
+			wins++
+		}
+	}
+	return float32(wins) / float32(matches)
+}
+
+func scripted(p player) func(m int) player {
+	return func(m int) player { return p }
+}
+
+func TestAIBeatsScriptedOpponents(t *testing.T) {
+	const matches = 40
+	easyVsStill := winRate(t, Easy, scripted(still), matches)
+	t.Logf("EASY beats STILL %.0f%% of the time", 100*easyVsStill)
+	if easyVsStill < 0.95 {
+		t.Errorf("EASY only beat a paddle that doesn't move %.0f%% of the time", 100*easyVsStill)
+	}
+
+	easyVsTracker := winRate(t, Easy, scripted(tracker), matches)
+	hardVsTracker := winRate(t, Hard, scripted(tracker), matches)
+	t.Logf("against TRACKER EASY wins %.0f%% and HARD %.0f%%", 100*easyVsTracker, 100*hardVsTracker)
+	if hardVsTracker <= easyVsTracker {
+		t.Errorf("HARD beat TRACKER %.0f%% of the time, no more than EASY's %.0f%%", 100*hardVsTracker, 100*easyVsTracker)
+	}
+}
+
+func TestHarderAIsWin(t *testing.T) {
+	tests := []struct {
+		stronger, weaker Difficulty
+	}{
+		{Hard, Normal},
+		{Normal, Easy},
+		{Hard, Easy},
+	}
+	for _, tt := range tests {
+		weaker := tt.weaker
+		rate := winRate(t, tt.stronger, func(m int) player { return aiPlayer(weaker, int64(2*m+1)) }, 40)
+		t.Logf("%s beats %s %.0f%% of the time", tt.stronger.Name, tt.weaker.Name, 100*rate)
+		if rate <= 0.6 {
+			t.Errorf("%s only beat %s %.0f%% of the time", tt.stronger.Name, tt.weaker.Name, 100*rate)
+		}
+	}
+}
+
+func TestAIIsDeterministic(t *testing.T) {
+	a, okA := playMatch(aiPlayer(Normal, 5), aiPlayer(Normal, 6), 3)
+	b, okB := playMatch(aiPlayer(Normal, 5), aiPlayer(Normal, 6), 3)
+	if !okA || !okB {
+		t.Fatal("match never finished")
+	}
+	if a.ball != b.ball || a.paddles != b.paddles || a.winner != b.winner {
+		t.Fatalf("matches ended differently: ball %v and %v, paddles %v and %v", a.ball, b.ball, a.paddles, b.paddles)
+	}
+	if !sameEvents(a.events, b.events) {
+		t.Fatalf("matches had different events, %d and %d of them", len(a.events), len(b.events))
+	}
+
+	// and a different seed plays a different match
+	c, _ := playMatch(aiPlayer(Normal, 7), aiPlayer(Normal, 8), 3)
+	if sameEvents(a.events, c.events) && a.ball == c.ball {
+		t.Error("different seeds played the same match")
+	}
+}
+
+func TestAIKeepsNegativeTargets(t *testing.T) {
+	// aiming past the top of the field is a real target, not "no target yet"
+	g := New(DefaultConfig())
+	g.Phase = Play
+	g.Ball.Velocity.X = 100
+	ai := NewAI(Right, Hard, 1)
+	ai.Difficulty.ReactionTime = 1
+	if input := ai.Input(g, aiTimeStep); input.Move != 0 {
+		t.Fatalf("AI moved %v before it had picked a target", input.Move)
+	}
+
+	// still reacting, so it carries on towards the target it had
+	ai.target = -40
+	if input := ai.Input(g, aiTimeStep); input.Move != -ai.Difficulty.SpeedScale {
+		t.Errorf("AI moved %v towards a target above the field, want %v", input.Move, -ai.Difficulty.SpeedScale)
+	}
+}
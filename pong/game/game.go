@@ -0,0 +1,377 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector2"
+)
+
+// Side is one of the two players
+type Side int
+
+const (
+	// Left is the player on the left, player one
+	Left Side = iota
+	// Right is the player on the right, player two
+	Right
+)
+
+// NoSide is the Side of events that aren't about either player
+const NoSide Side = -1
+
+// Other returns the opposing side of Left or Right
+func (s Side) Other() Side {
+	return 1 - s
+}
+
+// Phase is what the game is doing
+type Phase int
+
+const (
+	// Serve is waiting for a player to serve the ball
+	Serve Phase = iota
+	// Play is a rally in progress
+	Play
+	// MatchOver is after a player has won, serving starts a new match
+	MatchOver
+)
+
+// EventType is something that happened during a step
+type EventType int
+
+const (
+	// Served is a rally starting
+	Served EventType = iota
+	// WallBounce is the ball bouncing off the top or bottom, its Side is NoSide
+	WallBounce
+	// PaddleHit is the ball bouncing off Side's paddle
+	PaddleHit
+	// Scored is Side winning a point
+	Scored
+	// MatchWon is Side winning the match
+	MatchWon
+)
+
+// Event is something that happened during a step, for sounds and effects
+type Event struct {
+	Type EventType
+	Side Side
+}
+
+// Input is what a player is doing this step
+type Input struct {
+	// Move is -1 to move the paddle up at full speed and 1 to move it down
+	Move float32
+	// Serve starts the next rally
+	Serve bool
+}
+
+// Config sets the size and speed of everything
+type Config struct {
+	Width, Height float32
+	PaddleWidth   float32
+	PaddleHeight  float32
+	// PaddleSpeed and the ball speeds are in pixels per second
+	PaddleSpeed float32
+	// PaddleInset is how far each paddle's center is from its end of the field
+	PaddleInset float32
+	BallRadius  float32
+	// ServeSpeed is how fast the ball starts each rally, every paddle hit adds
+	// SpeedUp until it reaches MaxBallSpeed
+	ServeSpeed   float32
+	SpeedUp      float32
+	MaxBallSpeed float32
+	// MaxServeAngle is the furthest in radians a serve is from horizontal
+	MaxServeAngle float32
+	// MaxBounceAngle is how far in radians from horizontal the ball leaves a paddle
+	// when it hits the very end, a hit in the middle goes straight back
+	MaxBounceAngle float32
+	// Spin is how much of a moving paddle's speed is passed on to the ball
+	Spin float32
+	// Seed picks the serve angles, the same seed and inputs play the same game
+	Seed int64
+	// WinScore is the score that wins a match
+	WinScore int
+}
+
+// DefaultConfig returns the classic 800x600 game
+func DefaultConfig() Config {
+	return Config{
+		Width:          800,
+		Height:         600,
+		PaddleWidth:    20,
+		PaddleHeight:   100,
+		PaddleSpeed:    300,
+		PaddleInset:    50,
+		BallRadius:     20,
+		ServeSpeed:     450,
+		SpeedUp:        25,
+		MaxBallSpeed:   900,
+		MaxServeAngle:  math.Pi / 6,
+		MaxBounceAngle: math.Pi / 3,
+		Spin:           0.3,
+		WinScore:       11,
+	}
+}
+
+// Paddle is a player's bat
+type Paddle struct {
+	Pos           vector2.Vector2
+	Width, Height float32
+	Speed         float32
+	// Velocity is how fast the paddle moved up or down in the last step
+	Velocity float32
+	Score    int
+}
+
+// Bounds returns the box the paddle covers
+func (p *Paddle) Bounds() geometry.AABB {
+	halfSize := vector2.Vector2{X: p.Width / 2, Y: p.Height / 2}
+	return geometry.NewAABB(p.Pos.Sub(halfSize).Vector3(0), p.Pos.Add(halfSize).Vector3(0))
+}
+
+// Ball is the ball
+type Ball struct {
+	Pos      vector2.Vector2
+	Velocity vector2.Vector2
+	Radius   float32
+}
+
+// Game is a game of pong with no display or input of its own. Feed it inputs with
+// Step and draw it from its fields, which are there to be read.
+type Game struct {
+	Config  Config
+	Phase   Phase
+	Paddles [2]Paddle
+	Ball    Ball
+	// Server is who serves the next rally, it swaps after every point
+	Server Side
+	// Winner is who won the last match, it only means something in MatchOver
+	Winner Side
+
+	rng    *rand.Rand
+	events []Event
+}
+
+// New returns a game waiting for the first serve
+func New(cfg Config) *Game {
+	g := &Game{Config: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}
+	for i := range g.Paddles {
+		g.Paddles[i] = Paddle{Width: cfg.PaddleWidth, Height: cfg.PaddleHeight, Speed: cfg.PaddleSpeed}
+	}
+	g.Ball = Ball{Pos: g.Center(), Radius: cfg.BallRadius}
+	g.resetPaddles()
+	return g
+}
+
+// Center returns the middle of the field
+func (g *Game) Center() vector2.Vector2 {
+	return vector2.Vector2{X: g.Config.Width / 2, Y: g.Config.Height / 2}
+}
+
+func (g *Game) resetPaddles() {
+	g.Paddles[Left].Pos = vector2.Vector2{X: g.Config.PaddleInset, Y: g.Center().Y}
+	g.Paddles[Right].Pos = vector2.Vector2{X: g.Config.Width - g.Config.PaddleInset, Y: g.Center().Y}
+	g.Paddles[Left].Velocity = 0
+	g.Paddles[Right].Velocity = 0
+}
+
+// serve starts a rally with the ball heading away from the server at a random angle
+func (g *Game) serve() {
+	angle := (g.rng.Float32()*2 - 1) * g.Config.MaxServeAngle
+	g.Ball.Pos = g.Center()
+	g.Ball.Velocity = g.launch(g.Server.Other(), g.Config.ServeSpeed, angle)
+	g.Phase = Play
+	g.emit(Served, g.Server)
+}
+
+// launch returns a velocity towards side's end at speed and angle radians from horizontal
+func (g *Game) launch(side Side, speed, angle float32) vector2.Vector2 {
+	sin, cos := math.Sincos(float64(angle))
+	v := vector2.Vector2{X: speed * float32(cos), Y: speed * float32(sin)}
+	if side == Left {
+		v.X = -v.X
+	}
+	return v
+}
+
+func (g *Game) emit(t EventType, side Side) {
+	g.events = append(g.events, Event{t, side})
+}
+
+// Step advances the game by elapsedTime seconds and returns what happened. The
+// events are only valid until the next call to Step.
+func (g *Game) Step(inputs [2]Input, elapsedTime float32) []Event {
+	g.events = g.events[:0]
+
+	switch g.Phase {
+	case Serve, MatchOver:
+		g.resetPaddles()
+		if inputs[Left].Serve || inputs[Right].Serve {
+			if g.Phase == MatchOver {
+				g.Paddles[Left].Score = 0
+				g.Paddles[Right].Score = 0
+			}
+			g.serve()
+		}
+	case Play:
+		for side := range g.Paddles {
+			g.movePaddle(&g.Paddles[side], inputs[side], elapsedTime)
+		}
+		g.moveBall(elapsedTime)
+	}
+	return g.events
+}
+
+func (g *Game) movePaddle(p *Paddle, input Input, elapsedTime float32) {
+	move := input.Move
+	if move > 1 {
+		move = 1
+	} else if move < -1 {
+		move = -1
+	}
+	startY := p.Pos.Y
+	p.Pos.Y += move * p.Speed * elapsedTime
+
+	// keep the paddle on the field
+	if p.Pos.Y < p.Height/2 {
+		p.Pos.Y = p.Height / 2
+	} else if p.Pos.Y > g.Config.Height-p.Height/2 {
+		p.Pos.Y = g.Config.Height - p.Height/2
+	}
+	if elapsedTime > 0 {
+		p.Velocity = (p.Pos.Y - startY) / elapsedTime
+	}
+}
+
+func (g *Game) moveBall(elapsedTime float32) {
+	ball := &g.Ball
+	ball.Pos = ball.Pos.Add(ball.Velocity.Mult(elapsedTime))
+
+	if ball.Pos.Y-ball.Radius < 0 {
+		ball.Velocity.Y = -ball.Velocity.Y
+		ball.Pos.Y = ball.Radius
+		g.emit(WallBounce, NoSide)
+	} else if ball.Pos.Y+ball.Radius > g.Config.Height {
+		ball.Velocity.Y = -ball.Velocity.Y
+		ball.Pos.Y = g.Config.Height - ball.Radius
+		g.emit(WallBounce, NoSide)
+	}
+
+	if ball.Pos.X < 0 {
+		g.score(Right)
+		return
+	} else if ball.Pos.X > g.Config.Width {
+		g.score(Left)
+		return
+	}
+
+	sphere := geometry.Sphere{Center: ball.Pos.Vector3(0), Radius: ball.Radius}
+	for side := Left; side <= Right; side++ {
+		if g.Heading(side) && sphere.OverlapsAABB(g.Paddles[side].Bounds()) {
+			g.bounce(side)
+		}
+	}
+}
+
+// bounce sends the ball back from side's paddle. Where it hits the paddle picks
+// the angle, the paddle's movement adds spin and every hit makes it faster.
+func (g *Game) bounce(side Side) {
+	ball := &g.Ball
+	paddle := &g.Paddles[side]
+	ball.Pos.X = g.contactX(side)
+
+	// -1 at the top end of the paddle to 1 at the bottom end
+	reach := paddle.Height/2 + ball.Radius
+	offset := (ball.Pos.Y - paddle.Pos.Y) / reach
+	if offset > 1 {
+		offset = 1
+	} else if offset < -1 {
+		offset = -1
+	}
+
+	speed := ball.Velocity.Length() + g.Config.SpeedUp
+	if speed > g.Config.MaxBallSpeed {
+		speed = g.Config.MaxBallSpeed
+	}
+
+	// spin pushes the ball the way the paddle is moving, without going past the steepest angle
+	angle := offset * g.Config.MaxBounceAngle
+	v := g.launch(side.Other(), speed, angle)
+	v.Y += paddle.Velocity * g.Config.Spin
+	angle = float32(math.Atan2(float64(v.Y), math.Abs(float64(v.X))))
+	if angle > g.Config.MaxBounceAngle {
+		angle = g.Config.MaxBounceAngle
+	} else if angle < -g.Config.MaxBounceAngle {
+		angle = -g.Config.MaxBounceAngle
+	}
+	ball.Velocity = g.launch(side.Other(), speed, angle)
+	g.emit(PaddleHit, side)
+}
+
+func (g *Game) score(side Side) {
+	g.Paddles[side].Score++
+	g.Ball.Pos = g.Center()
+	g.Ball.Velocity = vector2.Vector2{}
+	g.Server = g.Server.Other()
+	g.emit(Scored, side)
+
+	if g.Paddles[side].Score >= g.Config.WinScore {
+		g.Winner = side
+		g.Phase = MatchOver
+		g.emit(MatchWon, side)
+		return
+	}
+	g.Phase = Serve
+}
+
+// contactX returns the x the ball's center is at when it touches side's paddle
+func (g *Game) contactX(side Side) float32 {
+	p := &g.Paddles[side]
+	if side == Left {
+		return p.Pos.X + p.Width/2 + g.Ball.Radius
+	}
+	return p.Pos.X - p.Width/2 - g.Ball.Radius
+}
+
+// Heading returns whether the ball is in play and moving towards side's paddle
+func (g *Game) Heading(side Side) bool {
+	if g.Phase != Play {
+		return false
+	}
+	if side == Left {
+		return g.Ball.Velocity.X < 0
+	}
+	return g.Ball.Velocity.X > 0
+}
+
+// PredictBall returns the y the ball will be at when it reaches side's paddle,
+// bouncing off the walls on the way, and how many seconds until it gets there.
+// ok is false if the ball isn't heading towards the paddle.
+func (g *Game) PredictBall(side Side) (y, t float32, ok bool) {
+	if !g.Heading(side) {
+		return 0, 0, false
+	}
+	ball := &g.Ball
+	t = (g.contactX(side) - ball.Pos.X) / ball.Velocity.X
+	if t < 0 {
+		t = 0
+	}
+
+	// unfold the bounces, the ball's center moves between Radius and Height-Radius
+	low := ball.Radius
+	span := g.Config.Height - 2*ball.Radius
+	if span <= 0 {
+		return g.Center().Y, t, true
+	}
+	y = float32(math.Mod(float64(ball.Pos.Y-low+ball.Velocity.Y*t), float64(2*span)))
+	if y < 0 {
+		y += 2 * span
+	}
+	if y > span {
+		y = 2*span - y
+	}
+	return low + y, t, true
+}
@@ -0,0 +1,169 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/vector2"
+)
+
+var noInput [2]Input
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-3
+}
+
+func sameEvents(a, b []Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// playing returns a game mid rally with the ball at pos moving at velocity
+func playing(pos, velocity vector2.Vector2) *Game {
+	g := New(DefaultConfig())
+	g.Phase = Play
+	g.Ball.Pos = pos
+	g.Ball.Velocity = velocity
+	return g
+}
+
+func TestServe(t *testing.T) {
+	g := New(DefaultConfig())
+	if events := g.Step(noInput, 0.1); len(events) != 0 || g.Phase != Serve {
+		t.Fatalf("game started without a serve: phase %v, events %v", g.Phase, events)
+	}
+
+	events := g.Step([2]Input{{Serve: true}, {}}, 0.1)
+	if !sameEvents(events, []Event{{Served, Left}}) || g.Phase != Play {
+		t.Fatalf("serving gave phase %v and events %v", g.Phase, events)
+	}
+	// the ball heads away from the server at the serve speed, no steeper than the serve angle
+	v := g.Ball.Velocity
+	if v.X <= 0 || !near(v.Length(), g.Config.ServeSpeed) {
+		t.Errorf("served at %v, want %v towards the right", v, g.Config.ServeSpeed)
+	}
+	if angle := math.Atan2(math.Abs(float64(v.Y)), float64(v.X)); angle > float64(g.Config.MaxServeAngle)+1e-6 {
+		t.Errorf("served at %v radians, the most is %v", angle, g.Config.MaxServeAngle)
+	}
+}
+
+func TestWallBounce(t *testing.T) {
+	tests := []struct {
+		name  string
+		y, vy float32
+		wantY float32
+	}{
+		{"top", 25, -300, 20},
+		{"bottom", 575, 300, 580},
+	}
+	for _, tt := range tests {
+		g := playing(vector2.Vector2{X: 400, Y: tt.y}, vector2.Vector2{X: 100, Y: tt.vy})
+		events := g.Step(noInput, 0.1)
+		if !sameEvents(events, []Event{{WallBounce, NoSide}}) {
+			t.Errorf("%s: events are %v", tt.name, events)
+		}
+		if g.Ball.Velocity.Y != -tt.vy || g.Ball.Pos.Y != tt.wantY {
+			t.Errorf("%s: ball is at %v moving %v", tt.name, g.Ball.Pos, g.Ball.Velocity)
+		}
+	}
+}
+
+func TestPaddleHit(t *testing.T) {
+	cfg := DefaultConfig()
+	contactX := cfg.Width - cfg.PaddleInset - cfg.PaddleWidth/2 - cfg.BallRadius
+
+	// straight into the middle of a still paddle comes straight back, a bit faster
+	g := playing(vector2.Vector2{X: contactX - 5, Y: 300}, vector2.Vector2{X: 450})
+	events := g.Step(noInput, 0.02)
+	if !sameEvents(events, []Event{{PaddleHit, Right}}) {
+		t.Fatalf("events are %v", events)
+	}
+	if v := g.Ball.Velocity; !near(v.X, -475) || !near(v.Y, 0) || g.Ball.Pos.X != contactX {
+		t.Errorf("ball left the paddle at %v moving %v", g.Ball.Pos, v)
+	}
+
+	// further from the middle sends it off at a steeper angle
+	reach := cfg.PaddleHeight/2 + cfg.BallRadius
+	g = playing(vector2.Vector2{X: contactX - 5, Y: 300 + cfg.PaddleHeight/2}, vector2.Vector2{X: 450})
+	g.Step(noInput, 0.02)
+	angle := math.Atan2(float64(g.Ball.Velocity.Y), -float64(g.Ball.Velocity.X))
+	if want := float64(cfg.PaddleHeight / 2 / reach * cfg.MaxBounceAngle); math.Abs(angle-want) > 1e-4 {
+		t.Errorf("end of the paddle sent the ball off at %v radians, want %v", angle, want)
+	}
+
+	// the ball never goes faster than MaxBallSpeed
+	g = playing(vector2.Vector2{X: contactX - 5, Y: 300}, vector2.Vector2{X: cfg.MaxBallSpeed})
+	g.Step(noInput, 0.001)
+	if speed := g.Ball.Velocity.Length(); !near(speed, cfg.MaxBallSpeed) {
+		t.Errorf("ball sped up to %v, the most is %v", speed, cfg.MaxBallSpeed)
+	}
+}
+
+func TestScoring(t *testing.T) {
+	g := playing(vector2.Vector2{X: 5, Y: 100}, vector2.Vector2{X: -450})
+	events := g.Step(noInput, 0.1)
+	if !sameEvents(events, []Event{{Scored, Right}}) {
+		t.Fatalf("events are %v", events)
+	}
+	if g.Paddles[Right].Score != 1 || g.Paddles[Left].Score != 0 {
+		t.Errorf("scores are %d to %d", g.Paddles[Left].Score, g.Paddles[Right].Score)
+	}
+	if g.Phase != Serve || g.Server != Right || g.Ball.Pos != g.Center() || g.Ball.Velocity != (vector2.Vector2{}) {
+		t.Errorf("after a point the phase is %v, %v serves, ball at %v moving %v", g.Phase, g.Server, g.Ball.Pos, g.Ball.Velocity)
+	}
+}
+
+func TestMatchOver(t *testing.T) {
+	g := playing(vector2.Vector2{X: 795, Y: 100}, vector2.Vector2{X: 450})
+	g.Paddles[Left].Score = g.Config.WinScore - 1
+	g.Paddles[Right].Score = 3
+
+	events := g.Step(noInput, 0.1)
+	if !sameEvents(events, []Event{{Scored, Left}, {MatchWon, Left}}) {
+		t.Fatalf("events are %v", events)
+	}
+	if g.Phase != MatchOver || g.Winner != Left {
+		t.Fatalf("phase is %v and the winner %v", g.Phase, g.Winner)
+	}
+
+	// nothing happens until someone serves, which starts a new match
+	if events := g.Step([2]Input{{Move: 1}, {Move: -1}}, 0.1); len(events) != 0 || g.Phase != MatchOver {
+		t.Fatalf("match over gave phase %v and events %v", g.Phase, events)
+	}
+	g.Step([2]Input{{}, {Serve: true}}, 0.1)
+	if g.Phase != Play || g.Paddles[Left].Score != 0 || g.Paddles[Right].Score != 0 {
+		t.Errorf("new match is in phase %v with scores %d to %d", g.Phase, g.Paddles[Left].Score, g.Paddles[Right].Score)
+	}
+}
+
+func TestPaddlesStayOnField(t *testing.T) {
+	g := playing(vector2.Vector2{X: 400, Y: 300}, vector2.Vector2{Y: 1})
+	for i := 0; i < 100; i++ {
+		g.Step([2]Input{{Move: -5}, {Move: 5}}, 0.05)
+	}
+	left, right := g.Paddles[Left], g.Paddles[Right]
+	if left.Pos.Y != left.Height/2 || right.Pos.Y != g.Config.Height-right.Height/2 {
+		t.Errorf("paddles ended up at %v and %v", left.Pos.Y, right.Pos.Y)
+	}
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = 9
+	a, b := New(cfg), New(cfg)
+	for i := 0; i < 5000; i++ {
+		// a fixed pattern of inputs, serving whenever the ball is dead
+		inputs := [2]Input{{Move: float32(i%7-3) / 3, Serve: true}, {Move: float32(i%5-2) / 2, Serve: true}}
+		ea, eb := a.Step(inputs, 1.0/60), b.Step(inputs, 1.0/60)
+		if !sameEvents(ea, eb) || a.Ball != b.Ball || a.Paddles != b.Paddles {
+			t.Fatalf("games went different ways at step %d", i)
+		}
+	}
+}
@@ -2,193 +2,139 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/sabith-th/games_with_go/anim"
+	"github.com/sabith-th/games_with_go/colors"
+	"github.com/sabith-th/games_with_go/font"
+	"github.com/sabith-th/games_with_go/pong/game"
+	"github.com/sabith-th/games_with_go/vector2"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 const winWidth, winHeight int = 800, 600
 
-type gameState int
-
-const (
-	start gameState = iota
-	play
-)
-
-var state = start
-
-var nums = [][]byte{
-	{
-		1, 1, 1,
-		1, 0, 1,
-		1, 0, 1,
-		1, 0, 1,
-		1, 1, 1,
-	},
-	{
-		1, 1, 0,
-		0, 1, 0,
-		0, 1, 0,
-		0, 1, 0,
-		1, 1, 1,
-	},
-	{
-		1, 1, 1,
-		0, 0, 1,
-		1, 1, 1,
-		1, 0, 0,
-		1, 1, 1,
-	},
-	{
-		1, 1, 1,
-		0, 0, 1,
-		0, 1, 1,
-		0, 0, 1,
-		1, 1, 1,
-	},
-}
-
-type color struct {
-	r, g, b byte
-}
-
 func clear(pixels []byte) {
 	for i := range pixels {
 		pixels[i] = 0
 	}
 }
 
-func setPixel(x, y int, c color, pixels []byte) {
+func setPixel(x, y int, c colors.Color, pixels []byte) {
 	index := (y*winWidth + x) * 4
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
 }
 
-func drawNumber(position position, color color, size int, num int, pixels []byte) {
-	startX := int(position.x) - (size*3)/2
-	startY := int(position.y) - (size*5)/2
+type align int
 
-	for i, v := range nums[num] {
-		if v == 1 {
-			for y := startY; y < startY+size; y++ {
-				for x := startX; x < startX+size; x++ {
-					setPixel(x, y, color, pixels)
-				}
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+// drawText draws text with each font pixel size by size screen pixels. pos is the
+// vertical middle of the text and its left edge, middle or right edge depending on a.
+func drawText(text string, pos vector2.Vector2, size int, color colors.Color, a align, pixels []byte) {
+	x := int(pos.X)
+	switch a {
+	case alignCenter:
+		x -= font.Width(text, size) / 2
+	case alignRight:
+		x -= font.Width(text, size)
+	}
+	y := int(pos.Y) - font.Height(size)/2
+	font.Draw(text, x, y, size, color, pixels, winWidth, winHeight)
+}
+
+func drawBall(ball *game.Ball, color colors.Color, pixels []byte) {
+	for y := -ball.Radius; y < ball.Radius; y++ {
+		for x := -ball.Radius; x < ball.Radius; x++ {
+			if x*x+y*y < ball.Radius*ball.Radius {
+				setPixel(int(ball.Pos.X+x), int(ball.Pos.Y+y), color, pixels)
 			}
 		}
-		startX += size
-		if (i+1)%3 == 0 {
-			startY += size
-			startX -= size * 3
-		}
 	}
-
-}
-
-func lerp(a float32, b float32, pct float32) float32 {
-	return a + pct*(b-a)
 }
 
-type position struct {
-	x, y float32
-}
+func drawPaddle(paddle *game.Paddle, center vector2.Vector2, color colors.Color, pixels []byte) {
+	startX := int(paddle.Pos.X - paddle.Width/2)
+	startY := int(paddle.Pos.Y - paddle.Height/2)
 
-func getCenter() position {
-	return position{float32(winWidth) / 2, float32(winHeight) / 2}
-}
-
-type ball struct {
-	position
-	radius float32
-	xv     float32
-	yv     float32
-	color  color
-}
-
-func (ball *ball) draw(pixels []byte) {
-	for y := -ball.radius; y < ball.radius; y++ {
-		for x := -ball.radius; x < ball.radius; x++ {
-			if x*x+y*y < ball.radius*ball.radius {
-				setPixel(int(ball.x+x), int(ball.y+y), ball.color, pixels)
-			}
+	for y := 0; y < int(paddle.Height); y++ {
+		for x := 0; x < int(paddle.Width); x++ {
+			setPixel(startX+x, startY+y, color, pixels)
 		}
 	}
-}
 
-func (ball *ball) update(leftPaddle, rightPaddle *paddle, elapsedTime float32) {
-	ball.x += ball.xv * elapsedTime
-	ball.y += ball.yv * elapsedTime
-
-	if ball.y-ball.radius < 0 {
-		ball.yv = -ball.yv
-		ball.y = ball.radius
-	} else if ball.y+ball.radius > float32(winHeight) {
-		ball.yv = -ball.yv
-		ball.y = float32(winHeight) - ball.radius
-	}
+	numX := anim.Lerp(paddle.Pos.X, center.X, 0.2)
+	drawText(strconv.Itoa(paddle.Score), vector2.Vector2{X: numX, Y: 35}, 10, color, alignCenter, pixels)
+}
 
-	if ball.x < 0 {
-		rightPaddle.score++
-		ball.position = getCenter()
-		state = start
-	} else if int(ball.x) > winWidth {
-		leftPaddle.score++
-		ball.position = getCenter()
-		state = start
-	}
+var playerNames = [2]string{"PLAYER 1", "PLAYER 2"}
 
-	if ball.x-ball.radius < leftPaddle.x+leftPaddle.width/2 {
-		if ball.y > leftPaddle.y-leftPaddle.height/2 && ball.y < leftPaddle.y+leftPaddle.height/2 {
-			ball.xv = -ball.xv
-			ball.x = leftPaddle.x + leftPaddle.width/2.0 + ball.radius
+func drawMessage(g *game.Game, gameMode mode, difficulty game.Difficulty, serveKey string, paddleColors [2]colors.Color, pixels []byte) {
+	white := colors.RGB(255, 255, 255)
+	center := g.Center()
+	if g.Phase != game.Play || gameMode == attract {
+		settings := "TAB: " + modeNames[gameMode]
+		if gameMode != humanVsHuman {
+			settings += "   F1: " + difficulty.Name
 		}
+		drawText(settings, vector2.Vector2{X: center.X, Y: float32(winHeight) - 30}, 3, white, alignCenter, pixels)
+	}
+	if gameMode == attract {
+		return
 	}
-	if ball.x+ball.radius > rightPaddle.x-rightPaddle.width/2 {
-		if ball.y > rightPaddle.y-rightPaddle.height/2 && ball.y < rightPaddle.y+rightPaddle.height/2 {
-			ball.xv = -ball.xv
-			ball.x = rightPaddle.x - rightPaddle.width/2.0 - ball.radius
+	switch g.Phase {
+	case game.Serve:
+		if g.Paddles[game.Left].Score == 0 && g.Paddles[game.Right].Score == 0 {
+			drawText("PONG", vector2.Vector2{X: center.X, Y: center.Y - 120}, 16, white, alignCenter, pixels)
+			drawText(fmt.Sprintf("FIRST TO %d", g.Config.WinScore), vector2.Vector2{X: center.X, Y: center.Y + 80}, 4, white, alignCenter, pixels)
 		}
+		drawText("PRESS "+serveKey+" TO SERVE", vector2.Vector2{X: center.X, Y: center.Y + 120}, 4, white, alignCenter, pixels)
+	case game.MatchOver:
+		drawText(playerNames[g.Winner]+" WINS!", vector2.Vector2{X: center.X, Y: center.Y - 100}, 8, paddleColors[g.Winner], alignCenter, pixels)
+		drawText("PRESS "+serveKey+" TO PLAY AGAIN", vector2.Vector2{X: center.X, Y: center.Y + 120}, 4, white, alignCenter, pixels)
 	}
 }
 
-type paddle struct {
-	position
-	width  float32
-	height float32
-	speed  float32
-	score  int
-	color  color
-}
+type mode int
+
+const (
+	humanVsAI mode = iota
+	humanVsHuman
+	attract
+)
 
-func (paddle *paddle) draw(pixels []byte) {
-	startX := int(paddle.x - paddle.width/2)
-	startY := int(paddle.y - paddle.height/2)
+var modeNames = []string{"1 PLAYER", "2 PLAYERS", "ATTRACT"}
 
-	for y := 0; y < int(paddle.height); y++ {
-		for x := 0; x < int(paddle.width); x++ {
-			setPixel(startX+x, startY+y, paddle.color, pixels)
-		}
+// humans returns which players are people, the rest are played by the computer
+func (m mode) humans() [2]bool {
+	switch m {
+	case humanVsAI:
+		return [2]bool{true, false}
+	case humanVsHuman:
+		return [2]bool{true, true}
 	}
-
-	numX := lerp(paddle.x, getCenter().x, 0.2)
-	drawNumber(position{numX, 35}, paddle.color, 10, paddle.score, pixels)
+	return [2]bool{false, false}
 }
 
-func (paddle *paddle) update(keyState []uint8, elapsedTime float32) {
-	if keyState[sdl.SCANCODE_UP] != 0 {
-		paddle.y -= paddle.speed * elapsedTime
-	}
-	if keyState[sdl.SCANCODE_DOWN] != 0 {
-		paddle.y += paddle.speed * elapsedTime
-	}
+func newAIs(difficulty game.Difficulty) [2]*game.AI {
+	seed := time.Now().UnixNano()
+	return [2]*game.AI{game.NewAI(game.Left, difficulty, seed), game.NewAI(game.Right, difficulty, seed+1)}
 }
 
-func (paddle *paddle) aiUpdate(ball *ball, elapsedTime float32) {
-	paddle.y = ball.y
+func newGame() *game.Game {
+	cfg := game.DefaultConfig()
+	cfg.Width, cfg.Height = float32(winWidth), float32(winHeight)
+	cfg.Seed = time.Now().UnixNano()
+	return game.New(cfg)
 }
 
 func main() {
@@ -225,9 +171,19 @@ func main() {
 
 	pixels := make([]byte, winWidth*winHeight*4)
 
-	player1 := paddle{position{50, getCenter().y}, 20, 100, 300, 0, color{255, 0, 0}}
-	player2 := paddle{position{float32(winWidth) - 50, getCenter().y}, 20, 100, 300, 0, color{0, 0, 255}}
-	ball := ball{getCenter(), 20, 400, 400, color{204, 255, 0}}
+	g := newGame()
+	gameMode := humanVsAI
+	difficulty := 1
+	ais := newAIs(game.Difficulties[difficulty])
+	controls, err := loadControls()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	controls.openControllers()
+	defer controls.closeControllers()
+	paddleColors := [2]colors.Color{colors.RGB(255, 0, 0), colors.RGB(0, 0, 255)}
+	ballColor := colors.RGB(204, 255, 0)
 
 	keyState := sdl.GetKeyboardState()
 
@@ -238,33 +194,50 @@ func main() {
 		frameStart = time.Now()
 
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
-			switch event.(type) {
+			switch e := event.(type) {
 			case *sdl.QuitEvent:
 				return
+			case *sdl.ControllerDeviceEvent:
+				if e.Type == sdl.CONTROLLERDEVICEADDED || e.Type == sdl.CONTROLLERDEVICEREMOVED {
+					controls.openControllers()
+				}
+			case *sdl.KeyboardEvent:
+				if e.Type != sdl.KEYDOWN || e.Repeat != 0 {
+					continue
+				}
+				switch e.Keysym.Scancode {
+				case sdl.SCANCODE_TAB:
+					// the next mode starts a new match
+					gameMode = (gameMode + 1) % mode(len(modeNames))
+					g = newGame()
+					ais = newAIs(game.Difficulties[difficulty])
+				case sdl.SCANCODE_F1:
+					difficulty = (difficulty + 1) % len(game.Difficulties)
+					ais = newAIs(game.Difficulties[difficulty])
+				}
 			}
 		}
 
-		if state == play {
-			player1.update(keyState, elapsedTime)
-			player2.aiUpdate(&ball, elapsedTime)
-			ball.update(&player1, &player2, elapsedTime)
-		} else if state == start {
-			player1.position = position{50, getCenter().y}
-			player2.position = position{float32(winWidth) - 50, getCenter().y}
-			if keyState[sdl.SCANCODE_SPACE] != 0 {
-				if player1.score == 3 || player2.score == 3 {
-					player1.score = 0
-					player2.score = 0
-				}
-				state = play
+		var inputs [2]game.Input
+		humans := gameMode.humans()
+		for side := range inputs {
+			if humans[side] {
+				inputs[side] = controls.input(keyState, side, gameMode == humanVsAI)
+			} else {
+				inputs[side] = ais[side].Input(g, elapsedTime)
+				// the computer only serves for itself when nobody is playing
+				inputs[side].Serve = gameMode == attract
 			}
 		}
+		g.Step(inputs, elapsedTime)
 
 		clear(pixels)
 
-		player1.draw(pixels)
-		player2.draw(pixels)
-		ball.draw(pixels)
+		for side := range g.Paddles {
+			drawPaddle(&g.Paddles[side], g.Center(), paddleColors[side], pixels)
+		}
+		drawBall(&g.Ball, ballColor, pixels)
+		drawMessage(g, gameMode, game.Difficulties[difficulty], controls.serveName(), paddleColors, pixels)
 
 		tex.Update(nil, pixels, winWidth*4)
 		renderer.Copy(tex, nil, nil)
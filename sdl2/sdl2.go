@@ -3,21 +3,18 @@ package main
 import (
 	"fmt"
 
+	"github.com/sabith-th/games_with_go/colors"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 const winWidth, winHeight int = 800, 600
 
-type color struct {
-	r, g, b byte
-}
-
-func setPixel(x, y int, c color, pixels []byte) {
+func setPixel(x, y int, c colors.Color, pixels []byte) {
 	index := (y*winWidth + x) * 4
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
 }
 
@@ -49,7 +46,7 @@ func main() {
 	pixels := make([]byte, winWidth*winHeight*4)
 	for y := 0; y < winHeight; y++ {
 		for x := 0; x < winWidth; x++ {
-			setPixel(x, y, color{byte(x % 255), byte(y % 255), 0}, pixels)
+			setPixel(x, y, colors.RGB(byte(x%255), byte(y%255), 0), pixels)
 		}
 	}
 
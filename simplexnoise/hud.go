@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sabith-th/games_with_go/colors"
+	"github.com/sabith-th/games_with_go/font"
+)
+
+const hudX, hudY, hudSize = 10, 10, 2
+
+var hudColor = colors.RGB(255, 255, 255)
+
+// darken dims a rectangle of pixels so text drawn over it stays readable
+func darken(x, y, w, h int, pixels []byte) {
+	for py := y; py < y+h && py < winHeight; py++ {
+		for px := x; px < x+w && px < winWidth; px++ {
+			index := (py*winWidth + px) * 4
+			pixels[index] /= 3
+			pixels[index+1] /= 3
+			pixels[index+2] /= 3
+		}
+	}
+}
+
+// drawHUD draws the current parameters, the gradient and the last message over the noise
+func drawHUD(pixels []byte, p params, elapsedTime float64, message string) {
+	lines := []string{
+		"T TYPE: " + p.noiseType().name,
+		fmt.Sprintf("O OCTAVES: %d", p.Octaves),
+		fmt.Sprintf("F FREQUENCY: %.3f", p.Frequency),
+		fmt.Sprintf("G GAIN: %.2f", p.Gain),
+		fmt.Sprintf("L LACUNARITY: %.2f", p.Lacunarity),
+		fmt.Sprintf("C GRADIENT: %s", p.gradient().name),
+		fmt.Sprintf("TIME: %.1fMS", elapsedTime),
+		"1-4 LOAD, SHIFT+1-4 SAVE",
+		"P SAVE PNG, H HIDE",
+	}
+	if message != "" {
+		lines = append(lines, message)
+	}
+
+	lineHeight := font.Height(hudSize) + hudSize*2
+	width := 0
+	for _, line := range lines {
+		if w := font.Width(line, hudSize); w > width {
+			width = w
+		}
+	}
+	stripHeight := lineHeight
+	darken(0, 0, width+hudX*2, len(lines)*lineHeight+stripHeight+hudY*2, pixels)
+
+	y := hudY
+	for _, line := range lines {
+		font.Draw(line, hudX, y, hudSize, hudColor, pixels, winWidth, winHeight)
+		y += lineHeight
+	}
+
+	gradient := p.gradient().colors
+	for x := 0; x < width; x++ {
+		c := gradient[x*(len(gradient)-1)/width]
+		for i := 0; i < font.Height(hudSize); i++ {
+			setPixel(hudX+x, y+i, c, pixels)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+func presetName(slot int) string {
+	return fmt.Sprintf("preset%d.json", slot)
+}
+
+// savePreset writes p to the preset file for slot and returns a message for the HUD
+func savePreset(slot int, p params) string {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+		return "SAVE FAILED"
+	}
+	err = ioutil.WriteFile(presetName(slot), data, 0644)
+	if err != nil {
+		fmt.Println(err)
+		return "SAVE FAILED"
+	}
+	return fmt.Sprintf("SAVED PRESET %d", slot)
+}
+
+// loadPreset reads the preset file for slot into p, p is only changed if the file loads
+func loadPreset(slot int, p *params) (string, bool) {
+	data, err := ioutil.ReadFile(presetName(slot))
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Sprintf("NO PRESET %d", slot), false
+	}
+	loaded := *p
+	err = json.Unmarshal(data, &loaded)
+	if err != nil {
+		fmt.Println(err)
+		return fmt.Sprintf("BAD PRESET %d", slot), false
+	}
+	if loaded.Octaves < 1 {
+		loaded.Octaves = 1
+	}
+	*p = loaded
+	return fmt.Sprintf("LOADED PRESET %d", slot), true
+}
+
+// exportPNG saves rgba pixels to a timestamped png and returns a message for the HUD
+func exportPNG(pixels []byte, w, h int) string {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	copy(img.Pix, pixels)
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	filename := fmt.Sprintf("noise-%s.png", time.Now().Format("20060102-150405"))
+	file, err := os.Create(filename)
+	if err != nil {
+		fmt.Println(err)
+		return "EXPORT FAILED"
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		fmt.Println(err)
+		return "EXPORT FAILED"
+	}
+	return "SAVED " + filename
+}
@@ -1,156 +1,154 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"math"
-	"runtime"
-	"sync"
 	"time"
 
+	"github.com/sabith-th/games_with_go/colors"
+	"github.com/sabith-th/games_with_go/noise"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 const winWidth, winHeight int = 800, 600
 
-type color struct {
-	r, g, b byte
+func clamp(min, max, v int) int {
+	if v < min {
+		v = min
+	} else if v > max {
+		v = max
+	}
+	return v
+}
+
+func rescaleAndDraw(values []float32, min, max float32, gradient []colors.Color, pixels []byte) {
+	// flat noise, like at zero frequency, is drawn with the bottom of the gradient
+	scale := float32(0)
+	if max > min {
+		scale = 255.0 / (max - min)
+	}
+	offset := min * scale
+
+	for i := range values {
+		values[i] = values[i]*scale - offset
+		c := gradient[clamp(0, 255, int(values[i]))]
+		p := i * 4
+		pixels[p] = c.R
+		pixels[p+1] = c.G
+		pixels[p+2] = c.B
+	}
 }
 
-func lerp(b1, b2 byte, pct float32) byte {
-	return byte(float32(b1) + pct*(float32(b2)-float32(b1)))
+type gradient struct {
+	name   string
+	colors []colors.Color
 }
 
-func colorlerp(c1, c2 color, pct float32) color {
-	return color{lerp(c1.r, c2.r, pct), lerp(c1.g, c2.g, pct), lerp(c1.b, c2.b, pct)}
+var gradients = []gradient{
+	{"OCEAN", colors.GetDualGradient(colors.RGB(0, 0, 175), colors.RGB(80, 160, 244), colors.RGB(12, 192, 75), colors.RGB(255, 255, 255))},
+	{"CLOUDS", colors.GetGradient(colors.RGB(0, 0, 255), colors.RGB(255, 255, 255))},
+	{"FIRE", colors.NewGradient(colors.OKLab, colors.Stop{Pos: 0, Color: colors.RGB(0, 0, 0)},
+		colors.Stop{Pos: 0.4, Color: colors.RGB(200, 30, 0)}, colors.Stop{Pos: 0.8, Color: colors.RGB(255, 200, 0)},
+		colors.Stop{Pos: 1, Color: colors.RGB(255, 255, 255)}).LUT()},
+	{"GRAY", colors.GetGradient(colors.RGB(0, 0, 0), colors.RGB(255, 255, 255))},
 }
 
-func getGradient(c1, c2 color) []color {
-	result := make([]color, 256)
-	for i := range result {
-		pct := float32(i) / float32(255)
-		result[i] = colorlerp(c1, c2, pct)
-	}
-	return result
+type noiseType struct {
+	name   string
+	module func(p params) noise.Module
 }
 
-func getDualGradient(c1, c2, c3, c4 color) []color {
-	result := make([]color, 256)
-	for i := range result {
-		pct := float32(i) / float32(255)
-		if pct < 0.5 {
-			result[i] = colorlerp(c1, c2, pct*float32(2))
-		} else {
-			result[i] = colorlerp(c3, c4, pct*float32(1.5)-float32(0.5))
-		}
+func simplex(t noise.Type) func(p params) noise.Module {
+	return func(p params) noise.Module {
+		return &noise.Simplex{Type: t, Frequency: p.Frequency, Lacunarity: p.Lacunarity, Gain: p.Gain, Octaves: p.Octaves}
 	}
-	return result
 }
 
-func clamp(min, max, v int) int {
-	if v < min {
-		v = min
-	} else if v > max {
-		v = max
+func worley(t noise.Type) func(p params) noise.Module {
+	return func(p params) noise.Module {
+		return &noise.Worley{Type: t, Frequency: p.Frequency, Lacunarity: p.Lacunarity, Gain: p.Gain, Octaves: p.Octaves}
 	}
-	return v
 }
 
-func rescaleAndDraw(noise []float32, min, max float32, gradient []color, pixels []byte) {
-	scale := 255.0 / (max - min)
-	offset := min * scale
+var noiseTypes = []noiseType{
+	{"TURBULENCE", simplex(noise.TURBULENCE)},
+	{"FBM", simplex(noise.FBM)},
+	{"DAMPED FBM", simplex(noise.DAMPED)},
+	{"WORLEY", func(p params) noise.Module { return &noise.Worley{Frequency: p.Frequency} }},
+	{"WORLEY FBM", worley(noise.FBM)},
+	{"WORLEY TURBULENCE", worley(noise.TURBULENCE)},
+}
 
-	for i := range noise {
-		noise[i] = noise[i]*scale - offset
-		c := gradient[clamp(0, 255, int(noise[i]))]
-		p := i * 4
-		pixels[p] = c.r
-		pixels[p+1] = c.g
-		pixels[p+2] = c.b
+// params are the settings the noise is generated from, they are saved as presets
+type params struct {
+	Frequency  float32 `json:"frequency"`
+	Gain       float32 `json:"gain"`
+	Lacunarity float32 `json:"lacunarity"`
+	Octaves    int     `json:"octaves"`
+	Gradient   string  `json:"gradient"`
+	Noise      string  `json:"noise"`
+}
+
+func (p *params) gradient() gradient {
+	for _, g := range gradients {
+		if g.name == p.Gradient {
+			return g
+		}
 	}
+	return gradients[0]
 }
 
-func turbulence(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
-	var sum float32
-	amplitude := float32(1.0)
-	for i := 0; i < octaves; i++ {
-		f := snoise2(x*frequency, y*frequency) * amplitude
-		if f < 0 {
-			f = -1.0 * f
+func (p *params) nextGradient() {
+	for i, g := range gradients {
+		if g.name == p.Gradient {
+			p.Gradient = gradients[(i+1)%len(gradients)].name
+			return
 		}
-		sum += f
-		frequency *= lacunarity
-		amplitude *= gain
 	}
-	return sum
+	p.Gradient = gradients[0].name
 }
 
-func fbm2(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
-	sum := float32(0.0)
-	amplitude := float32(1.0)
-	for i := 0; i < octaves; i++ {
-		sum += snoise2(x*frequency, y*frequency) * amplitude
-		frequency *= lacunarity
-		amplitude *= gain
+func (p *params) noiseType() noiseType {
+	for _, t := range noiseTypes {
+		if t.name == p.Noise {
+			return t
+		}
 	}
-	return sum
+	return noiseTypes[0]
 }
 
-func makeNoise(pixels []byte, frequency, lacunarity, gain float32, octaves int) {
-	var mutex = &sync.Mutex{}
-	startTime := time.Now()
-	noise := make([]float32, winWidth*winHeight)
-
-	min := float32(math.MaxFloat32)
-	max := float32(-math.MaxFloat32)
-
-	numRoutines := runtime.NumCPU()
-	var wg sync.WaitGroup
-	wg.Add(numRoutines)
-	batchSize := len(noise) / numRoutines
-
-	for i := 0; i < numRoutines; i++ {
-		go func(i int) {
-			defer wg.Done()
-			innerMin := float32(math.MaxFloat32)
-			innerMax := float32(-math.MaxFloat32)
-			start := i * batchSize
-			end := start + batchSize - 1
-			for j := start; j < end; j++ {
-				x := j % winWidth
-				y := (j - x) / winHeight
-				noise[j] = turbulence(float32(x), float32(y), frequency, lacunarity, gain, octaves)
-
-				if noise[j] < innerMin {
-					innerMin = noise[j]
-				} else if noise[j] > innerMax {
-					innerMax = noise[j]
-				}
-			}
+func (p *params) nextNoiseType() {
+	for i, t := range noiseTypes {
+		if t.name == p.Noise {
+			p.Noise = noiseTypes[(i+1)%len(noiseTypes)].name
+			return
+		}
+	}
+	p.Noise = noiseTypes[0].name
+}
 
-			mutex.Lock()
-			if innerMin < min {
-				min = innerMin
-			}
-			if innerMax > max {
-				max = innerMax
-			}
-			mutex.Unlock()
-		}(i)
+// makeNoise draws the noise into pixels and returns how long it took in milliseconds
+func makeNoise(pixels []byte, p params) float64 {
+	startTime := time.Now()
+	result, err := noise.MakeNoise(context.Background(), noise.Options{
+		Module: p.noiseType().module(p),
+		Width:  winWidth,
+		Height: winHeight,
+	})
+	if err != nil {
+		panic(err)
 	}
-	wg.Wait()
-	elapsedTime := time.Since(startTime).Seconds() * 1000.0
-	fmt.Println(elapsedTime)
 
-	gradient := getDualGradient(color{0, 0, 175}, color{80, 160, 244}, color{12, 192, 75}, color{255, 255, 255})
-	rescaleAndDraw(noise, min, max, gradient, pixels)
+	rescaleAndDraw(result.Noise, result.Min, result.Max, p.gradient().colors, pixels)
+	return time.Since(startTime).Seconds() * 1000.0
 }
 
-func setPixel(x, y int, c color, pixels []byte) {
+func setPixel(x, y int, c colors.Color, pixels []byte) {
 	index := (y*winWidth + x) * 4
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
 }
 
@@ -186,42 +184,78 @@ func main() {
 	}
 	defer tex.Destroy()
 
+	noisePixels := make([]byte, winWidth*winHeight*4)
 	pixels := make([]byte, winWidth*winHeight*4)
-	frequency := float32(0.01)
-	gain := float32(0.2)
-	lacunarity := float32(3.0)
-	octaves := 3
+	p := params{Frequency: 0.01, Gain: 0.2, Lacunarity: 3.0, Octaves: 3, Gradient: gradients[0].name, Noise: noiseTypes[0].name}
 
-	makeNoise(pixels, frequency, lacunarity, gain, octaves)
+	elapsedTime := makeNoise(noisePixels, p)
 	keyState := sdl.GetKeyboardState()
+	showHUD := true
+	message := ""
 
 	for {
+		changed := false
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
-			switch event.(type) {
+			switch e := event.(type) {
 			case *sdl.QuitEvent:
 				return
+			case *sdl.KeyboardEvent:
+				// only act on the first press, not on key repeats
+				if e.Type != sdl.KEYDOWN || e.Repeat != 0 {
+					continue
+				}
+
+				mult := 1
+				if keyState[sdl.SCANCODE_LSHIFT] != 0 || keyState[sdl.SCANCODE_RSHIFT] != 0 {
+					mult = -1
+				}
+
+				switch e.Keysym.Scancode {
+				case sdl.SCANCODE_O:
+					p.Octaves = p.Octaves + 1*mult
+					if p.Octaves < 1 {
+						p.Octaves = 1
+					}
+					changed = true
+				case sdl.SCANCODE_F:
+					p.Frequency = p.Frequency + 0.001*float32(mult)
+					changed = true
+				case sdl.SCANCODE_G:
+					p.Gain = p.Gain + 0.1*float32(mult)
+					changed = true
+				case sdl.SCANCODE_L:
+					p.Lacunarity = p.Lacunarity + 0.1*float32(mult)
+					changed = true
+				case sdl.SCANCODE_T:
+					p.nextNoiseType()
+					changed = true
+				case sdl.SCANCODE_C:
+					p.nextGradient()
+					changed = true
+				case sdl.SCANCODE_H:
+					showHUD = !showHUD
+				case sdl.SCANCODE_P:
+					message = exportPNG(noisePixels, winWidth, winHeight)
+				case sdl.SCANCODE_1, sdl.SCANCODE_2, sdl.SCANCODE_3, sdl.SCANCODE_4:
+					slot := int(e.Keysym.Scancode-sdl.SCANCODE_1) + 1
+					if mult < 0 {
+						message = savePreset(slot, p)
+					} else {
+						var loaded bool
+						message, loaded = loadPreset(slot, &p)
+						changed = loaded
+					}
+				}
 			}
 		}
 
-		mult := 1
-		if keyState[sdl.SCANCODE_LSHIFT] != 0 || keyState[sdl.SCANCODE_RSHIFT] != 0 {
-			mult = -1
+		if changed {
+			elapsedTime = makeNoise(noisePixels, p)
 		}
-		if keyState[sdl.SCANCODE_O] != 0 {
-			octaves = octaves + 1*mult
-			makeNoise(pixels, frequency, lacunarity, gain, octaves)
-		}
-		if keyState[sdl.SCANCODE_F] != 0 {
-			frequency = frequency + 0.001*float32(mult)
-			makeNoise(pixels, frequency, lacunarity, gain, octaves)
-		}
-		if keyState[sdl.SCANCODE_G] != 0 {
-			gain = gain + 0.1*float32(mult)
-			makeNoise(pixels, frequency, lacunarity, gain, octaves)
-		}
-		if keyState[sdl.SCANCODE_L] != 0 {
-			lacunarity = lacunarity + 0.001*float32(mult)
-			makeNoise(pixels, frequency, lacunarity, gain, octaves)
+
+		copy(pixels, noisePixels)
+		if showHUD {
+			drawHUD(pixels, p, elapsedTime, message)
 		}
 
 		tex.Update(nil, pixels, winWidth*4)
@@ -230,128 +264,3 @@ func main() {
 		sdl.Delay(16)
 	}
 }
-
-func fastFloor(x float32) int {
-	if float32(int(x)) <= x {
-		return int(x)
-	}
-	return int(x) - 1
-}
-
-// Static data
-
-/*
- * Permutation table. This is just a random jumble of all numbers 0-255
- * This needs to be exactly the same for all instances on all platforms,
- * so it's easiest to just keep it as static explicit data.
- * This also removes the need for any initialisation of this class.
- *
- */
-var perm = [256]uint8{151, 160, 137, 91, 90, 15,
-	131, 13, 201, 95, 96, 53, 194, 233, 7, 225, 140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23,
-	190, 6, 148, 247, 120, 234, 75, 0, 26, 197, 62, 94, 252, 219, 203, 117, 35, 11, 32, 57, 177, 33,
-	88, 237, 149, 56, 87, 174, 20, 125, 136, 171, 168, 68, 175, 74, 165, 71, 134, 139, 48, 27, 166,
-	77, 146, 158, 231, 83, 111, 229, 122, 60, 211, 133, 230, 220, 105, 92, 41, 55, 46, 245, 40, 244,
-	102, 143, 54, 65, 25, 63, 161, 1, 216, 80, 73, 209, 76, 132, 187, 208, 89, 18, 169, 200, 196,
-	135, 130, 116, 188, 159, 86, 164, 100, 109, 198, 173, 186, 3, 64, 52, 217, 226, 250, 124, 123,
-	5, 202, 38, 147, 118, 126, 255, 82, 85, 212, 207, 206, 59, 227, 47, 16, 58, 17, 182, 189, 28, 42,
-	223, 183, 170, 213, 119, 248, 152, 2, 44, 154, 163, 70, 221, 153, 101, 155, 167, 43, 172, 9,
-	129, 22, 39, 253, 19, 98, 108, 110, 79, 113, 224, 232, 178, 185, 112, 104, 218, 246, 97, 228,
-	251, 34, 242, 193, 238, 210, 144, 12, 191, 179, 162, 241, 81, 51, 145, 235, 249, 14, 239, 107,
-	49, 192, 214, 31, 181, 199, 106, 157, 184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254,
-	138, 236, 205, 93, 222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180}
-
-//---------------------------------------------------------------------
-
-func grad2(hash uint8, x, y float32) float32 {
-	h := hash & 7 // Convert low 3 bits of hash code
-	u := y
-	v := 2 * x
-	if h < 4 {
-		u = x
-		v = 2 * y
-	} // into 8 simple gradient directions,
-	// and compute the dot product with (x,y).
-
-	if h&1 != 0 {
-		u = -u
-	}
-	if h&2 != 0 {
-		v = -v
-	}
-	return u + v
-}
-
-// 2D simplex noise
-func snoise2(x, y float32) float32 {
-
-	const F2 float32 = 0.366025403 // F2 = 0.5*(sqrt(3.0)-1.0)
-	const G2 float32 = 0.211324865 // G2 = (3.0-Math.sqrt(3.0))/6.0
-
-	var n0, n1, n2 float32 // Noise contributions from the three corners
-
-	// Skew the input space to determine which simplex cell we're in
-	s := (x + y) * F2 // Hairy factor for 2D
-	xs := x + s
-	ys := y + s
-	i := fastFloor(xs)
-	j := fastFloor(ys)
-
-	t := float32(i+j) * G2
-	X0 := float32(i) - t // Unskew the cell origin back to (x,y) space
-	Y0 := float32(j) - t
-	x0 := x - X0 // The x,y distances from the cell origin
-	y0 := y - Y0
-
-	// For the 2D case, the simplex shape is an equilateral triangle.
-	// Determine which simplex we are in.
-	var i1, j1 uint8 // Offsets for second (middle) corner of simplex in (i,j) coords
-	if x0 > y0 {
-		i1 = 1
-		j1 = 0
-	} else { // lower triangle, XY order: (0,0)->(1,0)->(1,1)
-		i1 = 0
-		j1 = 1
-	} // upper triangle, YX order: (0,0)->(0,1)->(1,1)
-
-	// A step of (1,0) in (i,j) means a step of (1-c,-c) in (x,y), and
-	// a step of (0,1) in (i,j) means a step of (-c,1-c) in (x,y), where
-	// c = (3-sqrt(3))/6
-
-	x1 := x0 - float32(i1) + G2 // Offsets for middle corner in (x,y) unskewed coords
-	y1 := y0 - float32(j1) + G2
-	x2 := x0 - 1.0 + 2.0*G2 // Offsets for last corner in (x,y) unskewed coords
-	y2 := y0 - 1.0 + 2.0*G2
-
-	// Wrap the integer indices at 256, to avoid indexing perm[] out of bounds
-	ii := uint8(i)
-	jj := uint8(j)
-
-	// Calculate the contribution from the three corners
-	t0 := 0.5 - x0*x0 - y0*y0
-	if t0 < 0.0 {
-		n0 = 0.0
-	} else {
-		t0 *= t0
-		n0 = t0 * t0 * grad2(perm[ii+perm[jj]], x0, y0)
-	}
-
-	t1 := 0.5 - x1*x1 - y1*y1
-	if t1 < 0.0 {
-		n1 = 0.0
-	} else {
-		t1 *= t1
-		n1 = t1 * t1 * grad2(perm[ii+i1+perm[jj+j1]], x1, y1)
-	}
-
-	t2 := 0.5 - x2*x2 - y2*y2
-	if t2 < 0.0 {
-		n2 = 0.0
-	} else {
-		t2 *= t2
-		n2 = t2 * t2 * grad2(perm[ii+1+perm[jj+1]], x2, y2)
-	}
-
-	// Add contributions from each corner to get the final noise value.
-	return (n0 + n1 + n2)
-}
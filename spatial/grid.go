@@ -0,0 +1,261 @@
+package spatial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+type cell struct {
+	x, y, z int32
+}
+
+type gridObject struct {
+	bounds   geometry.AABB
+	min, max cell
+	// stamp is the last query that looked at the object, so objects spanning
+	// several cells are only reported once
+	stamp uint32
+}
+
+// Grid is a uniform grid of cubes, each object is listed in every cell it touches.
+// It works best when objects are about the size of a cell and spread out evenly.
+type Grid struct {
+	cellSize float32
+	cells    map[cell][]int
+	objects  map[int]*gridObject
+	stamp    uint32
+
+	// bounds holds every cell that has ever been used, ray queries stop when they leave it
+	bounds    geometry.AABB
+	hasBounds bool
+}
+
+// NewGrid returns an empty grid of cubes cellSize wide, it panics if cellSize isn't positive
+func NewGrid(cellSize float32) *Grid {
+	if !(cellSize > 0) || math.IsInf(float64(cellSize), 1) {
+		panic(fmt.Sprintf("spatial: invalid grid cell size %v", cellSize))
+	}
+	return &Grid{cellSize: cellSize, cells: make(map[cell][]int), objects: make(map[int]*gridObject)}
+}
+
+func (g *Grid) cellAt(p vector3.Vector3) cell {
+	return cell{
+		int32(math.Floor(float64(p.X / g.cellSize))),
+		int32(math.Floor(float64(p.Y / g.cellSize))),
+		int32(math.Floor(float64(p.Z / g.cellSize))),
+	}
+}
+
+// Insert adds an object, inserting an id that is already there moves it
+func (g *Grid) Insert(id int, bounds geometry.AABB) {
+	if _, ok := g.objects[id]; ok {
+		g.Move(id, bounds)
+		return
+	}
+	obj := &gridObject{bounds: bounds, min: g.cellAt(bounds.Min), max: g.cellAt(bounds.Max)}
+	g.objects[id] = obj
+	g.addToCells(id, obj)
+
+	if g.hasBounds {
+		g.bounds = g.bounds.Union(bounds)
+	} else {
+		g.bounds = bounds
+		g.hasBounds = true
+	}
+}
+
+func (g *Grid) addToCells(id int, obj *gridObject) {
+	for z := obj.min.z; z <= obj.max.z; z++ {
+		for y := obj.min.y; y <= obj.max.y; y++ {
+			for x := obj.min.x; x <= obj.max.x; x++ {
+				c := cell{x, y, z}
+				g.cells[c] = append(g.cells[c], id)
+			}
+		}
+	}
+}
+
+func (g *Grid) removeFromCells(id int, obj *gridObject) {
+	for z := obj.min.z; z <= obj.max.z; z++ {
+		for y := obj.min.y; y <= obj.max.y; y++ {
+			for x := obj.min.x; x <= obj.max.x; x++ {
+				c := cell{x, y, z}
+				ids := g.cells[c]
+				for i, other := range ids {
+					if other == id {
+						ids[i] = ids[len(ids)-1]
+						ids = ids[:len(ids)-1]
+						break
+					}
+				}
+				if len(ids) == 0 {
+					delete(g.cells, c)
+				} else {
+					g.cells[c] = ids
+				}
+			}
+		}
+	}
+}
+
+// Move changes the bounds of an object, it is cheap if the object stays in the same cells
+func (g *Grid) Move(id int, bounds geometry.AABB) {
+	obj, ok := g.objects[id]
+	if !ok {
+		g.Insert(id, bounds)
+		return
+	}
+	min, max := g.cellAt(bounds.Min), g.cellAt(bounds.Max)
+	if min != obj.min || max != obj.max {
+		g.removeFromCells(id, obj)
+		obj.min, obj.max = min, max
+		g.addToCells(id, obj)
+	}
+	obj.bounds = bounds
+	g.bounds = g.bounds.Union(bounds)
+}
+
+// Remove takes an object out of the grid
+func (g *Grid) Remove(id int) {
+	obj, ok := g.objects[id]
+	if !ok {
+		return
+	}
+	g.removeFromCells(id, obj)
+	delete(g.objects, id)
+}
+
+// Len returns how many objects are in the grid
+func (g *Grid) Len() int {
+	return len(g.objects)
+}
+
+// QueryRadius appends the ids of objects touching the sphere at center to dst.
+// Only cells inside the grid's bounds are visited, and if the sphere covers more
+// cells than are in use the cells in use are checked instead, so a huge radius
+// costs no more than a scan of every object.
+func (g *Grid) QueryRadius(center vector3.Vector3, radius float32, dst []int) []int {
+	if !g.hasBounds {
+		return dst
+	}
+	g.stamp++
+	sphere := geometry.Sphere{Center: center, Radius: radius}
+	// clamp before working out cells so huge spheres don't overflow the cell coordinates
+	box := sphere.Bounds()
+	box.Min = box.Min.Max(g.bounds.Min)
+	box.Max = box.Max.Min(g.bounds.Max)
+	if box.Min.X > box.Max.X || box.Min.Y > box.Max.Y || box.Min.Z > box.Max.Z {
+		return dst
+	}
+	min, max := g.cellAt(box.Min), g.cellAt(box.Max)
+
+	cellCount := int64(max.x-min.x+1) * int64(max.y-min.y+1) * int64(max.z-min.z+1)
+	if cellCount > int64(len(g.cells)) {
+		for c, ids := range g.cells {
+			if c.x >= min.x && c.x <= max.x && c.y >= min.y && c.y <= max.y && c.z >= min.z && c.z <= max.z {
+				dst = g.checkSphere(sphere, ids, dst)
+			}
+		}
+		return dst
+	}
+
+	for z := min.z; z <= max.z; z++ {
+		for y := min.y; y <= max.y; y++ {
+			for x := min.x; x <= max.x; x++ {
+				dst = g.checkSphere(sphere, g.cells[cell{x, y, z}], dst)
+			}
+		}
+	}
+	return dst
+}
+
+// checkSphere appends the ids that touch sphere and haven't been seen by this query yet
+func (g *Grid) checkSphere(sphere geometry.Sphere, ids []int, dst []int) []int {
+	for _, id := range ids {
+		obj := g.objects[id]
+		if obj.stamp == g.stamp {
+			continue
+		}
+		obj.stamp = g.stamp
+		if sphere.OverlapsAABB(obj.bounds) {
+			dst = append(dst, id)
+		}
+	}
+	return dst
+}
+
+// QueryRay appends the objects the ray hits before maxT to dst, nearest first.
+// It walks the cells along the ray one at a time.
+func (g *Grid) QueryRay(ray geometry.Ray, maxT float32, dst []Hit) []Hit {
+	// a zero direction never crosses into another cell
+	if !g.hasBounds || zeroDir(ray) {
+		return dst
+	}
+	tStart, hit := ray.IntersectAABB(g.bounds)
+	if !hit || tStart > maxT {
+		return dst
+	}
+	g.stamp++
+	start := len(dst)
+
+	boundsMin, boundsMax := g.cellAt(g.bounds.Min), g.cellAt(g.bounds.Max)
+	current := g.cellAt(ray.At(tStart))
+	pos := [3]*int32{&current.x, &current.y, &current.z}
+	lo := [3]int32{boundsMin.x, boundsMin.y, boundsMin.z}
+	hi := [3]int32{boundsMax.x, boundsMax.y, boundsMax.z}
+	origin := [3]float32{ray.Origin.X, ray.Origin.Y, ray.Origin.Z}
+	dir := [3]float32{ray.Dir.X, ray.Dir.Y, ray.Dir.Z}
+
+	// for each axis, the t where the ray crosses into the next cell and how much t a whole cell takes
+	var step [3]int32
+	var tNext, tDelta [3]float32
+	for i := 0; i < 3; i++ {
+		switch {
+		case dir[i] > 0:
+			step[i] = 1
+			tNext[i] = (float32(*pos[i]+1)*g.cellSize - origin[i]) / dir[i]
+			tDelta[i] = g.cellSize / dir[i]
+		case dir[i] < 0:
+			step[i] = -1
+			tNext[i] = (float32(*pos[i])*g.cellSize - origin[i]) / dir[i]
+			tDelta[i] = -g.cellSize / dir[i]
+		default:
+			tNext[i] = math.MaxFloat32
+		}
+	}
+
+	for {
+		for _, id := range g.cells[current] {
+			obj := g.objects[id]
+			if obj.stamp == g.stamp {
+				continue
+			}
+			obj.stamp = g.stamp
+			if t, hit := ray.IntersectAABB(obj.bounds); hit && t <= maxT {
+				dst = append(dst, Hit{id, t})
+			}
+		}
+
+		axis := 0
+		if tNext[1] < tNext[axis] {
+			axis = 1
+		}
+		if tNext[2] < tNext[axis] {
+			axis = 2
+		}
+		if tNext[axis] > maxT {
+			break
+		}
+		*pos[axis] += step[axis]
+		if *pos[axis] < lo[axis] || *pos[axis] > hi[axis] {
+			break
+		}
+		tNext[axis] += tDelta[axis]
+	}
+
+	sortHits(dst[start:])
+	return dst
+}
@@ -0,0 +1,208 @@
+package spatial
+
+import (
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+type octreeNode struct {
+	bounds   geometry.AABB
+	depth    int
+	children *[8]*octreeNode
+	objects  []*octreeObject
+}
+
+type octreeObject struct {
+	id     int
+	bounds geometry.AABB
+	node   *octreeNode
+}
+
+// Octree splits space into eighths wherever objects crowd together. Each object lives
+// in the smallest node that holds all of it, objects outside the octree's bounds live
+// in the root. It copes better than a Grid with objects of mixed sizes or clumped together.
+type Octree struct {
+	root       *octreeNode
+	objects    map[int]*octreeObject
+	maxObjects int
+	maxDepth   int
+}
+
+// NewOctree returns an empty octree covering bounds. A node splits when it holds more
+// than maxObjects, unless it is already maxDepth levels down.
+func NewOctree(bounds geometry.AABB, maxObjects, maxDepth int) *Octree {
+	return &Octree{
+		root:       &octreeNode{bounds: bounds},
+		objects:    make(map[int]*octreeObject),
+		maxObjects: maxObjects,
+		maxDepth:   maxDepth,
+	}
+}
+
+// childFor returns the child of node that holds all of bounds, or nil if it straddles them
+func (node *octreeNode) childFor(bounds geometry.AABB) *octreeNode {
+	if node.children == nil {
+		return nil
+	}
+	center := node.bounds.Center()
+	index := 0
+	if bounds.Min.X >= center.X {
+		index |= 1
+	} else if bounds.Max.X > center.X {
+		return nil
+	}
+	if bounds.Min.Y >= center.Y {
+		index |= 2
+	} else if bounds.Max.Y > center.Y {
+		return nil
+	}
+	if bounds.Min.Z >= center.Z {
+		index |= 4
+	} else if bounds.Max.Z > center.Z {
+		return nil
+	}
+	child := node.children[index]
+	if !child.bounds.Contains(bounds.Min) || !child.bounds.Contains(bounds.Max) {
+		return nil
+	}
+	return child
+}
+
+func (node *octreeNode) split() {
+	var children [8]*octreeNode
+	center := node.bounds.Center()
+	for i := range children {
+		min, max := node.bounds.Min, center
+		if i&1 != 0 {
+			min.X, max.X = center.X, node.bounds.Max.X
+		}
+		if i&2 != 0 {
+			min.Y, max.Y = center.Y, node.bounds.Max.Y
+		}
+		if i&4 != 0 {
+			min.Z, max.Z = center.Z, node.bounds.Max.Z
+		}
+		children[i] = &octreeNode{bounds: geometry.AABB{Min: min, Max: max}, depth: node.depth + 1}
+	}
+	node.children = &children
+}
+
+func (node *octreeNode) remove(obj *octreeObject) {
+	for i, other := range node.objects {
+		if other == obj {
+			last := len(node.objects) - 1
+			node.objects[i] = node.objects[last]
+			node.objects[last] = nil
+			node.objects = node.objects[:last]
+			return
+		}
+	}
+}
+
+func (o *Octree) insert(node *octreeNode, obj *octreeObject) {
+	for child := node.childFor(obj.bounds); child != nil; child = node.childFor(obj.bounds) {
+		node = child
+	}
+	node.objects = append(node.objects, obj)
+	obj.node = node
+
+	if node.children == nil && len(node.objects) > o.maxObjects && node.depth < o.maxDepth {
+		node.split()
+		objects := node.objects
+		node.objects = nil
+		for _, obj := range objects {
+			o.insert(node, obj)
+		}
+	}
+}
+
+// Insert adds an object, inserting an id that is already there moves it
+func (o *Octree) Insert(id int, bounds geometry.AABB) {
+	if _, ok := o.objects[id]; ok {
+		o.Move(id, bounds)
+		return
+	}
+	obj := &octreeObject{id: id, bounds: bounds}
+	o.objects[id] = obj
+	o.insert(o.root, obj)
+}
+
+// Move changes the bounds of an object, it is cheap if the object stays in the same node
+func (o *Octree) Move(id int, bounds geometry.AABB) {
+	obj, ok := o.objects[id]
+	if !ok {
+		o.Insert(id, bounds)
+		return
+	}
+	node := obj.node
+	obj.bounds = bounds
+	fits := node == o.root || (node.bounds.Contains(bounds.Min) && node.bounds.Contains(bounds.Max))
+	if fits && node.childFor(bounds) == nil {
+		return
+	}
+	node.remove(obj)
+	o.insert(o.root, obj)
+}
+
+// Remove takes an object out of the octree
+func (o *Octree) Remove(id int) {
+	obj, ok := o.objects[id]
+	if !ok {
+		return
+	}
+	obj.node.remove(obj)
+	delete(o.objects, id)
+}
+
+// Len returns how many objects are in the octree
+func (o *Octree) Len() int {
+	return len(o.objects)
+}
+
+// QueryRadius appends the ids of objects touching the sphere at center to dst
+func (o *Octree) QueryRadius(center vector3.Vector3, radius float32, dst []int) []int {
+	return o.queryRadius(o.root, geometry.Sphere{Center: center, Radius: radius}, dst)
+}
+
+func (o *Octree) queryRadius(node *octreeNode, sphere geometry.Sphere, dst []int) []int {
+	for _, obj := range node.objects {
+		if sphere.OverlapsAABB(obj.bounds) {
+			dst = append(dst, obj.id)
+		}
+	}
+	if node.children != nil {
+		for _, child := range node.children {
+			if sphere.OverlapsAABB(child.bounds) {
+				dst = o.queryRadius(child, sphere, dst)
+			}
+		}
+	}
+	return dst
+}
+
+// QueryRay appends the objects the ray hits before maxT to dst, nearest first
+func (o *Octree) QueryRay(ray geometry.Ray, maxT float32, dst []Hit) []Hit {
+	if zeroDir(ray) {
+		return dst
+	}
+	start := len(dst)
+	dst = o.queryRay(o.root, ray, maxT, dst)
+	sortHits(dst[start:])
+	return dst
+}
+
+func (o *Octree) queryRay(node *octreeNode, ray geometry.Ray, maxT float32, dst []Hit) []Hit {
+	for _, obj := range node.objects {
+		if t, hit := ray.IntersectAABB(obj.bounds); hit && t <= maxT {
+			dst = append(dst, Hit{obj.id, t})
+		}
+	}
+	if node.children != nil {
+		for _, child := range node.children {
+			if t, hit := ray.IntersectAABB(child.bounds); hit && t <= maxT {
+				dst = o.queryRay(child, ray, maxT, dst)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,115 @@
+package spatial
+
+import (
+	"sort"
+
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Index finds objects by where they are. Objects are identified by an id picked by
+// the caller and take up an axis aligned box. Indexes are not safe for concurrent use.
+type Index interface {
+	// Insert adds an object, inserting an id that is already there moves it
+	Insert(id int, bounds geometry.AABB)
+	// Move changes the bounds of an object
+	Move(id int, bounds geometry.AABB)
+	// Remove takes an object out of the index
+	Remove(id int)
+	// Len returns how many objects are in the index
+	Len() int
+	// QueryRadius appends the ids of objects touching the sphere at center to dst
+	QueryRadius(center vector3.Vector3, radius float32, dst []int) []int
+	// QueryRay appends the objects the ray hits before maxT to dst, nearest first.
+	// A ray with a zero direction doesn't go anywhere and hits nothing.
+	QueryRay(ray geometry.Ray, maxT float32, dst []Hit) []Hit
+}
+
+// Hit is an object hit by a ray, T is how far along the ray in units of its Dir
+type Hit struct {
+	ID int
+	T  float32
+}
+
+func zeroDir(ray geometry.Ray) bool {
+	return ray.Dir == (vector3.Vector3{})
+}
+
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool { return hits[i].T < hits[j].T })
+}
+
+// List is a brute force index that checks every object. It's the baseline the
+// other indexes are measured against and is fine for a few dozen objects.
+type List struct {
+	ids    []int
+	bounds []geometry.AABB
+	index  map[int]int
+}
+
+// NewList returns an empty list
+func NewList() *List {
+	return &List{index: make(map[int]int)}
+}
+
+// Insert adds an object, inserting an id that is already there moves it
+func (l *List) Insert(id int, bounds geometry.AABB) {
+	if i, ok := l.index[id]; ok {
+		l.bounds[i] = bounds
+		return
+	}
+	l.index[id] = len(l.ids)
+	l.ids = append(l.ids, id)
+	l.bounds = append(l.bounds, bounds)
+}
+
+// Move changes the bounds of an object
+func (l *List) Move(id int, bounds geometry.AABB) {
+	l.Insert(id, bounds)
+}
+
+// Remove takes an object out of the list
+func (l *List) Remove(id int) {
+	i, ok := l.index[id]
+	if !ok {
+		return
+	}
+	last := len(l.ids) - 1
+	l.ids[i] = l.ids[last]
+	l.bounds[i] = l.bounds[last]
+	l.index[l.ids[i]] = i
+	l.ids = l.ids[:last]
+	l.bounds = l.bounds[:last]
+	delete(l.index, id)
+}
+
+// Len returns how many objects are in the list
+func (l *List) Len() int {
+	return len(l.ids)
+}
+
+// QueryRadius appends the ids of objects touching the sphere at center to dst
+func (l *List) QueryRadius(center vector3.Vector3, radius float32, dst []int) []int {
+	sphere := geometry.Sphere{Center: center, Radius: radius}
+	for i, bounds := range l.bounds {
+		if sphere.OverlapsAABB(bounds) {
+			dst = append(dst, l.ids[i])
+		}
+	}
+	return dst
+}
+
+// QueryRay appends the objects the ray hits before maxT to dst, nearest first
+func (l *List) QueryRay(ray geometry.Ray, maxT float32, dst []Hit) []Hit {
+	if zeroDir(ray) {
+		return dst
+	}
+	start := len(dst)
+	for i, bounds := range l.bounds {
+		if t, hit := ray.IntersectAABB(bounds); hit && t <= maxT {
+			dst = append(dst, Hit{l.ids[i], t})
+		}
+	}
+	sortHits(dst[start:])
+	return dst
+}
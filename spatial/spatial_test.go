@@ -0,0 +1,241 @@
+package spatial
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/sabith-th/games_with_go/geometry"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+const numObjects = 10000
+const worldSize = 1000
+const objectSize = 10
+
+var indexes = []struct {
+	name string
+	new  func() Index
+}{
+	{"list", func() Index { return NewList() }},
+	{"grid", func() Index { return NewGrid(objectSize * 4) }},
+	{"octree", func() Index {
+		world := vector3.Vector3{X: worldSize, Y: worldSize, Z: worldSize}
+		return NewOctree(geometry.AABB{Max: world}, 16, 8)
+	}},
+}
+
+func randomPoint(rng *rand.Rand) vector3.Vector3 {
+	return vector3.Vector3{X: rng.Float32() * worldSize, Y: rng.Float32() * worldSize, Z: rng.Float32() * worldSize}
+}
+
+func objectBounds(p vector3.Vector3) geometry.AABB {
+	return geometry.Sphere{Center: p, Radius: objectSize / 2}.Bounds()
+}
+
+func fill(idx Index, rng *rand.Rand, n int) []vector3.Vector3 {
+	positions := make([]vector3.Vector3, n)
+	for i := range positions {
+		positions[i] = randomPoint(rng)
+		idx.Insert(i, objectBounds(positions[i]))
+	}
+	return positions
+}
+
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]int(nil), a...)
+	b = append([]int(nil), b...)
+	sort.Ints(a)
+	sort.Ints(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameHits compares ray hits by id, hits at nearly the same T can come back in either order
+func sameHits(a, b []Hit) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ts := make(map[int]float32, len(a))
+	for _, hit := range a {
+		ts[hit.ID] = hit.T
+	}
+	for _, hit := range b {
+		t, ok := ts[hit.ID]
+		if !ok || math.Abs(float64(t-hit.T)) > 1e-3 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndexesMatchList(t *testing.T) {
+	const n = 500
+	rng := rand.New(rand.NewSource(7))
+	list := NewList()
+	others := make([]Index, 0, len(indexes)-1)
+	for _, idx := range indexes[1:] {
+		others = append(others, idx.new())
+	}
+	all := append([]Index{list}, others...)
+
+	positions := make([]vector3.Vector3, n)
+	for i := range positions {
+		positions[i] = randomPoint(rng)
+		for _, idx := range all {
+			idx.Insert(i, objectBounds(positions[i]))
+		}
+	}
+
+	for step := 0; step < 20; step++ {
+		// shuffle things about, some objects wander outside the octree's bounds
+		for i := 0; i < n/4; i++ {
+			id := rng.Intn(n)
+			switch rng.Intn(3) {
+			case 0:
+				positions[id] = positions[id].Add(vector3.Vector3{X: rng.Float32()*100 - 50, Y: rng.Float32()*100 - 50, Z: rng.Float32()*100 - 50})
+				for _, idx := range all {
+					idx.Move(id, objectBounds(positions[id]))
+				}
+			case 1:
+				for _, idx := range all {
+					idx.Remove(id)
+				}
+			case 2:
+				positions[id] = randomPoint(rng).Mult(1.2)
+				for _, idx := range all {
+					idx.Insert(id, objectBounds(positions[id]))
+				}
+			}
+		}
+
+		for q := 0; q < 20; q++ {
+			center, radius := randomPoint(rng), rng.Float32()*100
+			want := list.QueryRadius(center, radius, nil)
+			ray := geometry.Ray{Origin: randomPoint(rng), Dir: randomPoint(rng).Sub(randomPoint(rng)).Normalize()}
+			wantHits := list.QueryRay(ray, worldSize, nil)
+
+			for i, idx := range others {
+				name := indexes[i+1].name
+				if idx.Len() != list.Len() {
+					t.Fatalf("%s holds %d objects, list holds %d", name, idx.Len(), list.Len())
+				}
+				if got := idx.QueryRadius(center, radius, nil); !sameIDs(got, want) {
+					t.Fatalf("%s radius query at %v, %v found %v, list found %v", name, center, radius, got, want)
+				}
+				hits := idx.QueryRay(ray, worldSize, nil)
+				if !sameHits(hits, wantHits) {
+					t.Fatalf("%s ray %v hit %v, list hit %v", name, ray, hits, wantHits)
+				}
+				for j := 1; j < len(hits); j++ {
+					if hits[j].T < hits[j-1].T {
+						t.Fatalf("%s hits aren't nearest first: %v", name, hits)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestZeroDirectionRay(t *testing.T) {
+	for _, idx := range indexes {
+		index := idx.new()
+		fill(index, rand.New(rand.NewSource(1)), 100)
+		ray := geometry.Ray{Origin: vector3.Vector3{X: 500, Y: 500, Z: 500}}
+		if hits := index.QueryRay(ray, math.MaxFloat32, nil); len(hits) != 0 {
+			t.Errorf("%s: zero direction ray hit %v", idx.name, hits)
+		}
+	}
+}
+
+func TestHugeRadius(t *testing.T) {
+	for _, idx := range indexes {
+		index := idx.new()
+		fill(index, rand.New(rand.NewSource(1)), 100)
+		for _, radius := range []float32{1e6, 1e30, math.MaxFloat32} {
+			if ids := index.QueryRadius(vector3.Vector3{}, radius, nil); len(ids) != 100 {
+				t.Errorf("%s: radius %v found %d objects, want 100", idx.name, radius, len(ids))
+			}
+		}
+	}
+}
+
+func TestNewGridRejectsBadCellSize(t *testing.T) {
+	for _, size := range []float32{0, -1, float32(math.NaN()), float32(math.Inf(1))} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewGrid(%v) didn't panic", size)
+				}
+			}()
+			NewGrid(size)
+		}()
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	for _, idx := range indexes {
+		b.Run(idx.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				fill(idx.new(), rand.New(rand.NewSource(1)), numObjects)
+			}
+		})
+	}
+}
+
+func BenchmarkMove(b *testing.B) {
+	for _, idx := range indexes {
+		b.Run(idx.name, func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			index := idx.new()
+			positions := fill(index, rng, numObjects)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// every object drifts a little, like a frame of a game
+				id := i % numObjects
+				p := positions[id].Add(vector3.Vector3{X: rng.Float32() - 0.5, Y: rng.Float32() - 0.5, Z: rng.Float32() - 0.5})
+				positions[id] = p
+				index.Move(id, objectBounds(p))
+			}
+		})
+	}
+}
+
+func BenchmarkRadius(b *testing.B) {
+	for _, idx := range indexes {
+		b.Run(idx.name, func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			index := idx.new()
+			fill(index, rng, numObjects)
+			var ids []int
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ids = index.QueryRadius(randomPoint(rng), 50, ids[:0])
+			}
+		})
+	}
+}
+
+func BenchmarkRay(b *testing.B) {
+	for _, idx := range indexes {
+		b.Run(idx.name, func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			index := idx.new()
+			fill(index, rng, numObjects)
+			var hits []Hit
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ray := geometry.Ray{Origin: randomPoint(rng), Dir: randomPoint(rng).Sub(randomPoint(rng)).Normalize()}
+				hits = index.QueryRay(ray, worldSize, hits[:0])
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package vector2
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Vector2 a 2d vector
+type Vector2 struct {
+	X, Y float32
+}
+
+// Add adds two vectors and returns a new vector
+func Add(a, b Vector2) Vector2 {
+	return Vector2{a.X + b.X, a.Y + b.Y}
+}
+
+// Sub subtracts b from a and returns a new vector
+func Sub(a, b Vector2) Vector2 {
+	return Vector2{a.X - b.X, a.Y - b.Y}
+}
+
+// Neg returns a vector pointing the opposite way
+func Neg(a Vector2) Vector2 {
+	return Vector2{-a.X, -a.Y}
+}
+
+// Mult multiplies a scalar to a vector and returns a new vector
+func Mult(a Vector2, b float32) Vector2 {
+	return Vector2{a.X * b, a.Y * b}
+}
+
+// Scale multiplies each component of a by the matching component of b
+func Scale(a, b Vector2) Vector2 {
+	return Vector2{a.X * b.X, a.Y * b.Y}
+}
+
+// Dot returns the dot product of two vectors
+func Dot(a, b Vector2) float32 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+// Cross returns the z component of the cross product of two vectors, it is
+// positive when b is anticlockwise of a
+func Cross(a, b Vector2) float32 {
+	return a.X*b.Y - a.Y*b.X
+}
+
+// Perp returns a rotated a quarter turn anticlockwise
+func Perp(a Vector2) Vector2 {
+	return Vector2{-a.Y, a.X}
+}
+
+// LengthSquared returns the squared magnitude of the given vector
+func LengthSquared(a Vector2) float32 {
+	return a.X*a.X + a.Y*a.Y
+}
+
+// Length returns the magnitude of the given vector
+func Length(a Vector2) float32 {
+	return float32(math.Sqrt(float64(LengthSquared(a))))
+}
+
+// Distance returns the distance between two vectors
+func Distance(a, b Vector2) float32 {
+	return Length(Sub(a, b))
+}
+
+// DistanceSquared returns the squared distance between two vectors
+func DistanceSquared(a, b Vector2) float32 {
+	return LengthSquared(Sub(a, b))
+}
+
+// Normalize returns a new vector with unit length and same direction as given vector.
+// A zero vector has no direction, so it is returned unchanged.
+func Normalize(a Vector2) Vector2 {
+	length := Length(a)
+	if length == 0 {
+		return Vector2{}
+	}
+	return Vector2{a.X / length, a.Y / length}
+}
+
+// Lerp linearly interpolates between two vectors
+func Lerp(a, b Vector2, pct float32) Vector2 {
+	return Vector2{
+		a.X + pct*(b.X-a.X),
+		a.Y + pct*(b.Y-a.Y),
+	}
+}
+
+// Reflect bounces a off a surface with unit normal n
+func Reflect(a, n Vector2) Vector2 {
+	return Sub(a, Mult(n, 2*Dot(a, n)))
+}
+
+// Project returns the part of a that points along b, it is zero if b is zero
+func Project(a, b Vector2) Vector2 {
+	lengthSquared := LengthSquared(b)
+	if lengthSquared == 0 {
+		return Vector2{}
+	}
+	return Mult(b, Dot(a, b)/lengthSquared)
+}
+
+// Angle returns the angle between two vectors in radians, it is zero if either is zero
+func Angle(a, b Vector2) float32 {
+	lengths := Length(a) * Length(b)
+	if lengths == 0 {
+		return 0
+	}
+	cos := Dot(a, b) / lengths
+	// rounding can push cos just outside -1 to 1
+	if cos > 1 {
+		cos = 1
+	} else if cos < -1 {
+		cos = -1
+	}
+	return float32(math.Acos(float64(cos)))
+}
+
+// Min returns the smallest of each component of two vectors
+func Min(a, b Vector2) Vector2 {
+	return Vector2{minf(a.X, b.X), minf(a.Y, b.Y)}
+}
+
+// Max returns the largest of each component of two vectors
+func Max(a, b Vector2) Vector2 {
+	return Vector2{maxf(a.X, b.X), maxf(a.Y, b.Y)}
+}
+
+// Clamp limits each component of a to between the components of lo and hi
+func Clamp(a, lo, hi Vector2) Vector2 {
+	return Min(Max(a, lo), hi)
+}
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func ApproxEqual(a, b Vector2, epsilon float32) bool {
+	return abs(a.X-b.X) <= epsilon && abs(a.Y-b.Y) <= epsilon
+}
+
+func minf(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(a float32) float32 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Conversions
+
+// FromVector3 returns the x and y of a, dropping z
+func FromVector3(a vector3.Vector3) Vector2 {
+	return Vector2{a.X, a.Y}
+}
+
+// ToVector3 returns a with z added
+func ToVector3(a Vector2, z float32) vector3.Vector3 {
+	return vector3.Vector3{X: a.X, Y: a.Y, Z: z}
+}
+
+// Methods
+
+// Add returns a + b
+func (a Vector2) Add(b Vector2) Vector2 { return Add(a, b) }
+
+// Sub returns a - b
+func (a Vector2) Sub(b Vector2) Vector2 { return Sub(a, b) }
+
+// Neg returns a vector pointing the opposite way
+func (a Vector2) Neg() Vector2 { return Neg(a) }
+
+// Mult returns a multiplied by the scalar b
+func (a Vector2) Mult(b float32) Vector2 { return Mult(a, b) }
+
+// Scale returns a multiplied component-wise by b
+func (a Vector2) Scale(b Vector2) Vector2 { return Scale(a, b) }
+
+// Dot returns the dot product of a and b
+func (a Vector2) Dot(b Vector2) float32 { return Dot(a, b) }
+
+// Cross returns the z component of the cross product of a and b
+func (a Vector2) Cross(b Vector2) float32 { return Cross(a, b) }
+
+// Perp returns a rotated a quarter turn anticlockwise
+func (a Vector2) Perp() Vector2 { return Perp(a) }
+
+// LengthSquared returns the squared magnitude of the vector
+func (a Vector2) LengthSquared() float32 { return LengthSquared(a) }
+
+// Length returns the magnitude of the vector
+func (a Vector2) Length() float32 { return Length(a) }
+
+// Distance returns the distance from a to b
+func (a Vector2) Distance(b Vector2) float32 { return Distance(a, b) }
+
+// DistanceSquared returns the squared distance from a to b
+func (a Vector2) DistanceSquared(b Vector2) float32 { return DistanceSquared(a, b) }
+
+// Normalize returns the vector with unit length, a zero vector stays zero
+func (a Vector2) Normalize() Vector2 { return Normalize(a) }
+
+// Lerp linearly interpolates from a to b
+func (a Vector2) Lerp(b Vector2, pct float32) Vector2 { return Lerp(a, b, pct) }
+
+// Reflect bounces a off a surface with unit normal n
+func (a Vector2) Reflect(n Vector2) Vector2 { return Reflect(a, n) }
+
+// Project returns the part of a that points along b
+func (a Vector2) Project(b Vector2) Vector2 { return Project(a, b) }
+
+// Angle returns the angle between a and b in radians
+func (a Vector2) Angle(b Vector2) float32 { return Angle(a, b) }
+
+// Min returns the smallest of each component of a and b
+func (a Vector2) Min(b Vector2) Vector2 { return Min(a, b) }
+
+// Max returns the largest of each component of a and b
+func (a Vector2) Max(b Vector2) Vector2 { return Max(a, b) }
+
+// Clamp limits each component of a to between the components of lo and hi
+func (a Vector2) Clamp(lo, hi Vector2) Vector2 { return Clamp(a, lo, hi) }
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func (a Vector2) ApproxEqual(b Vector2, epsilon float32) bool { return ApproxEqual(a, b, epsilon) }
+
+// Vector3 returns a with z added
+func (a Vector2) Vector3(z float32) vector3.Vector3 { return ToVector3(a, z) }
@@ -0,0 +1,88 @@
+package vector2
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// vec is a Vector2 testing/quick fills with components between -100 and 100,
+// the full float32 range would just test overflow
+type vec Vector2
+
+func (vec) Generate(r *rand.Rand, size int) reflect.Value {
+	c := func() float32 { return r.Float32()*200 - 100 }
+	return reflect.ValueOf(vec{c(), c()})
+}
+
+// near compares with a tolerance that grows with the size of the values involved
+func near(a, b, scale float32) bool {
+	return abs(a-b) <= 1e-4*(1+scale)
+}
+
+func nearVec(a, b Vector2, scale float32) bool {
+	return near(a.X, b.X, scale) && near(a.Y, b.Y, scale)
+}
+
+func check(t *testing.T, name string, f interface{}) {
+	t.Helper()
+	if err := quick.Check(f, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Errorf("%s: %v", name, err)
+	}
+}
+
+func TestDotSymmetric(t *testing.T) {
+	check(t, "a . b = b . a", func(a, b vec) bool {
+		return near(Dot(Vector2(a), Vector2(b)), Dot(Vector2(b), Vector2(a)), Length(Vector2(a))*Length(Vector2(b)))
+	})
+	check(t, "a . a = |a|^2", func(a vec) bool {
+		return near(Dot(Vector2(a), Vector2(a)), LengthSquared(Vector2(a)), LengthSquared(Vector2(a)))
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	check(t, "|normalize(a)| = 1", func(a vec) bool {
+		if Vector2(a) == (Vector2{}) {
+			return true
+		}
+		return near(Length(Normalize(Vector2(a))), 1, 0)
+	})
+	if n := Normalize(Vector2{}); n != (Vector2{}) {
+		t.Errorf("normalized zero vector is %v, want zero", n)
+	}
+	if n := (Vector2{}).Normalize(); n != (Vector2{}) {
+		t.Errorf("normalized zero vector method is %v, want zero", n)
+	}
+}
+
+func TestLerpEndpoints(t *testing.T) {
+	check(t, "lerp(a, b, 0) = a", func(a, b vec) bool {
+		return Lerp(Vector2(a), Vector2(b), 0) == Vector2(a)
+	})
+	check(t, "lerp(a, b, 1) = b", func(a, b vec) bool {
+		return nearVec(Lerp(Vector2(a), Vector2(b), 1), Vector2(b), Length(Vector2(a))+Length(Vector2(b)))
+	})
+}
+
+func TestLength(t *testing.T) {
+	if l := Length(Vector2{3, 4}); l != 5 {
+		t.Errorf("length of 3, 4 is %v, want 5", l)
+	}
+	check(t, "|a - b| = distance(a, b)", func(a, b vec) bool {
+		d := Distance(Vector2(a), Vector2(b))
+		return near(Length(Sub(Vector2(a), Vector2(b))), d, d)
+	})
+}
+
+func TestVector3(t *testing.T) {
+	a := Vector2{1.5, -2}
+	if v := a.Vector3(7); v != (vector3.Vector3{X: 1.5, Y: -2, Z: 7}) {
+		t.Errorf("%v with z 7 is %v", a, v)
+	}
+	if back := FromVector3(a.Vector3(7)); back != a {
+		t.Errorf("%v went through a Vector3 and came back as %v", a, back)
+	}
+}
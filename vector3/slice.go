@@ -0,0 +1,152 @@
+package vector3
+
+import "math"
+
+// Vector3Slice holds many vectors as three separate slices of components, for
+// particle systems and other big sets of vectors that are worked on all at once.
+// The slices are always the same length.
+type Vector3Slice struct {
+	X, Y, Z []float32
+}
+
+// NewVector3Slice returns a slice of n zero vectors
+func NewVector3Slice(n int) Vector3Slice {
+	return Vector3Slice{make([]float32, n), make([]float32, n), make([]float32, n)}
+}
+
+// FromVectors returns the vectors as a Vector3Slice
+func FromVectors(vectors []Vector3) Vector3Slice {
+	s := NewVector3Slice(len(vectors))
+	for i, v := range vectors {
+		s.X[i], s.Y[i], s.Z[i] = v.X, v.Y, v.Z
+	}
+	return s
+}
+
+// Vectors appends every vector in s to dst
+func (s Vector3Slice) Vectors(dst []Vector3) []Vector3 {
+	for i := range s.X {
+		dst = append(dst, Vector3{s.X[i], s.Y[i], s.Z[i]})
+	}
+	return dst
+}
+
+// Len returns the number of vectors
+func (s Vector3Slice) Len() int {
+	return len(s.X)
+}
+
+// At returns the vector at i
+func (s Vector3Slice) At(i int) Vector3 {
+	return Vector3{s.X[i], s.Y[i], s.Z[i]}
+}
+
+// Set changes the vector at i
+func (s Vector3Slice) Set(i int, v Vector3) {
+	s.X[i], s.Y[i], s.Z[i] = v.X, v.Y, v.Z
+}
+
+// Append adds vectors to the end of s and returns the new slice, like append
+func (s Vector3Slice) Append(vectors ...Vector3) Vector3Slice {
+	for _, v := range vectors {
+		s.X = append(s.X, v.X)
+		s.Y = append(s.Y, v.Y)
+		s.Z = append(s.Z, v.Z)
+	}
+	return s
+}
+
+// Swap swaps the vectors at i and j
+func (s Vector3Slice) Swap(i, j int) {
+	s.X[i], s.X[j] = s.X[j], s.X[i]
+	s.Y[i], s.Y[j] = s.Y[j], s.Y[i]
+	s.Z[i], s.Z[j] = s.Z[j], s.Z[i]
+}
+
+// Truncate returns s cut down to its first n vectors, for dropping dead particles
+// after swapping them to the end
+func (s Vector3Slice) Truncate(n int) Vector3Slice {
+	return Vector3Slice{s.X[:n], s.Y[:n], s.Z[:n]}
+}
+
+// The loops below reslice everything to the same length first so the compiler can
+// drop the bounds checks inside them.
+
+// AddScaled adds b multiplied by scale to each vector in s, like s[i] += b[i] * scale.
+// Use it to move positions by velocities. b must be at least as long as s.
+func (s Vector3Slice) AddScaled(b Vector3Slice, scale float32) {
+	x := s.X
+	y := s.Y[:len(x)]
+	z := s.Z[:len(x)]
+	bx := b.X[:len(x)]
+	by := b.Y[:len(x)]
+	bz := b.Z[:len(x)]
+	for i := range x {
+		x[i] += bx[i] * scale
+		y[i] += by[i] * scale
+		z[i] += bz[i] * scale
+	}
+}
+
+// NormalizeAll gives every vector in s unit length, zero vectors stay zero
+func (s Vector3Slice) NormalizeAll() {
+	x := s.X
+	y := s.Y[:len(x)]
+	z := s.Z[:len(x)]
+	for i := range x {
+		lengthSquared := x[i]*x[i] + y[i]*y[i] + z[i]*z[i]
+		if lengthSquared == 0 {
+			continue
+		}
+		inv := float32(1 / math.Sqrt(float64(lengthSquared)))
+		x[i] *= inv
+		y[i] *= inv
+		z[i] *= inv
+	}
+}
+
+// DistanceToPoint appends the distance from each vector in s to p to dst
+func (s Vector3Slice) DistanceToPoint(p Vector3, dst []float32) []float32 {
+	start := len(dst)
+	dst = s.DistanceSquaredToPoint(p, dst)
+	out := dst[start:]
+	for i, d := range out {
+		out[i] = float32(math.Sqrt(float64(d)))
+	}
+	return dst
+}
+
+// DistanceSquaredToPoint appends the squared distance from each vector in s to p to
+// dst, it is cheaper than DistanceToPoint when you only need to compare distances
+func (s Vector3Slice) DistanceSquaredToPoint(p Vector3, dst []float32) []float32 {
+	x := s.X
+	y := s.Y[:len(x)]
+	z := s.Z[:len(x)]
+	for i := range x {
+		dx := x[i] - p.X
+		dy := y[i] - p.Y
+		dz := z[i] - p.Z
+		dst = append(dst, dx*dx+dy*dy+dz*dz)
+	}
+	return dst
+}
+
+// Bounds returns the smallest and largest of each component, both are zero if s is empty
+func (s Vector3Slice) Bounds() (min, max Vector3) {
+	if len(s.X) == 0 {
+		return Vector3{}, Vector3{}
+	}
+	min.X, max.X = bounds(s.X)
+	min.Y, max.Y = bounds(s.Y)
+	min.Z, max.Z = bounds(s.Z)
+	return min, max
+}
+
+func bounds(a []float32) (lo, hi float32) {
+	lo, hi = a[0], a[0]
+	for _, v := range a[1:] {
+		lo = minf(lo, v)
+		hi = maxf(hi, v)
+	}
+	return lo, hi
+}
@@ -0,0 +1,162 @@
+package vector3
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const numVectors = 100000
+
+func randomVectors(seed int64, n int) []Vector3 {
+	rng := rand.New(rand.NewSource(seed))
+	vectors := make([]Vector3, n)
+	for i := range vectors {
+		vectors[i] = Vector3{X: rng.Float32()*200 - 100, Y: rng.Float32()*200 - 100, Z: rng.Float32()*200 - 100}
+	}
+	return vectors
+}
+
+func TestAddScaledMatchesStructs(t *testing.T) {
+	pos, vel := randomVectors(1, 1000), randomVectors(2, 1000)
+	s := FromVectors(pos)
+	s.AddScaled(FromVectors(vel), 0.016)
+	for i, v := range s.Vectors(nil) {
+		want := Add(pos[i], Mult(vel[i], 0.016))
+		if !nearVec(v, want, Length(want)) {
+			t.Fatalf("vector %d is %v, want %v", i, v, want)
+		}
+	}
+}
+
+func TestNormalizeAllMatchesStructs(t *testing.T) {
+	vectors := append(randomVectors(3, 1000), Vector3{}, Vector3{X: 1e-20})
+	s := FromVectors(vectors)
+	s.NormalizeAll()
+	for i, v := range s.Vectors(nil) {
+		if want := Normalize(vectors[i]); !nearVec(v, want, 0) {
+			t.Fatalf("vector %d normalized to %v, want %v", i, v, want)
+		}
+	}
+}
+
+func TestDistanceToPointMatchesStructs(t *testing.T) {
+	vectors := randomVectors(4, 1000)
+	p := Vector3{X: 1, Y: 2, Z: 3}
+	// the distances go after whatever is already in dst
+	dst := FromVectors(vectors).DistanceToPoint(p, []float32{-1})
+	if len(dst) != len(vectors)+1 || dst[0] != -1 {
+		t.Fatalf("dst has %d values starting %v", len(dst), dst[0])
+	}
+	squared := FromVectors(vectors).DistanceSquaredToPoint(p, nil)
+	for i, v := range vectors {
+		want := Distance(v, p)
+		if !near(dst[i+1], want, want) {
+			t.Fatalf("distance %d is %v, want %v", i, dst[i+1], want)
+		}
+		if !near(squared[i], want*want, want*want) {
+			t.Fatalf("squared distance %d is %v, want %v", i, squared[i], want*want)
+		}
+	}
+}
+
+func TestBoundsMatchesStructs(t *testing.T) {
+	vectors := randomVectors(5, 1000)
+	min, max := vectors[0], vectors[0]
+	for _, v := range vectors {
+		min = Min(min, v)
+		max = Max(max, v)
+	}
+	gotMin, gotMax := FromVectors(vectors).Bounds()
+	if gotMin != min || gotMax != max {
+		t.Errorf("bounds are %v to %v, want %v to %v", gotMin, gotMax, min, max)
+	}
+	if gotMin, gotMax := NewVector3Slice(0).Bounds(); gotMin != (Vector3{}) || gotMax != (Vector3{}) {
+		t.Errorf("empty bounds are %v to %v, want zero", gotMin, gotMax)
+	}
+}
+
+// The structs benchmarks are written the way the games move things today
+
+func BenchmarkAddScaled(b *testing.B) {
+	b.Run("structs", func(b *testing.B) {
+		pos, vel := randomVectors(1, numVectors), randomVectors(2, numVectors)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			for i := range pos {
+				pos[i] = Add(pos[i], Mult(vel[i], 0.016))
+			}
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		pos, vel := FromVectors(randomVectors(1, numVectors)), FromVectors(randomVectors(2, numVectors))
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			pos.AddScaled(vel, 0.016)
+		}
+	})
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	b.Run("structs", func(b *testing.B) {
+		vectors := randomVectors(1, numVectors)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			for i := range vectors {
+				vectors[i] = Normalize(vectors[i])
+			}
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		vectors := FromVectors(randomVectors(1, numVectors))
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			vectors.NormalizeAll()
+		}
+	})
+}
+
+func BenchmarkDistanceToPoint(b *testing.B) {
+	p := Vector3{X: 1, Y: 2, Z: 3}
+	b.Run("structs", func(b *testing.B) {
+		vectors := randomVectors(1, numVectors)
+		dst := make([]float32, 0, numVectors)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			dst = dst[:0]
+			for _, v := range vectors {
+				dst = append(dst, Distance(v, p))
+			}
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		vectors := FromVectors(randomVectors(1, numVectors))
+		dst := make([]float32, 0, numVectors)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			dst = vectors.DistanceToPoint(p, dst[:0])
+		}
+	})
+}
+
+func BenchmarkBounds(b *testing.B) {
+	b.Run("structs", func(b *testing.B) {
+		vectors := randomVectors(1, numVectors)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			min := Vector3{X: math.MaxFloat32, Y: math.MaxFloat32, Z: math.MaxFloat32}
+			max := Neg(min)
+			for _, v := range vectors {
+				min = Min(min, v)
+				max = Max(max, v)
+			}
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		vectors := FromVectors(randomVectors(1, numVectors))
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			vectors.Bounds()
+		}
+	})
+}
@@ -12,14 +12,48 @@ func Add(a, b Vector3) Vector3 {
 	return Vector3{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
 }
 
+// Sub subtracts b from a and returns a new vector
+func Sub(a, b Vector3) Vector3 {
+	return Vector3{a.X - b.X, a.Y - b.Y, a.Z - b.Z}
+}
+
+// Neg returns a vector pointing the opposite way
+func Neg(a Vector3) Vector3 {
+	return Vector3{-a.X, -a.Y, -a.Z}
+}
+
 // Mult multiplies a scalar to a vector and returns a new vector
 func Mult(a Vector3, b float32) Vector3 {
 	return Vector3{a.X * b, a.Y * b, a.Z * b}
 }
 
+// Scale multiplies each component of a by the matching component of b
+func Scale(a, b Vector3) Vector3 {
+	return Vector3{a.X * b.X, a.Y * b.Y, a.Z * b.Z}
+}
+
+// Dot returns the dot product of two vectors
+func Dot(a, b Vector3) float32 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+// Cross returns the cross product of two vectors, it is perpendicular to both
+func Cross(a, b Vector3) Vector3 {
+	return Vector3{
+		a.Y*b.Z - a.Z*b.Y,
+		a.Z*b.X - a.X*b.Z,
+		a.X*b.Y - a.Y*b.X,
+	}
+}
+
+// LengthSquared returns the squared magnitude of the given vector
+func LengthSquared(a Vector3) float32 {
+	return a.X*a.X + a.Y*a.Y + a.Z*a.Z
+}
+
 // Length returns the magnitude of the given vector
-func (a Vector3) Length() float32 {
-	return float32(math.Sqrt(float64(a.X*a.X + a.Y*a.Y + a.Z + a.Z)))
+func Length(a Vector3) float32 {
+	return float32(math.Sqrt(float64(LengthSquared(a))))
 }
 
 // Distance returns the distance between two vectors
@@ -38,8 +72,154 @@ func DistanceSquared(a, b Vector3) float32 {
 	return xDiff*xDiff + yDiff*yDiff + zDiff*zDiff
 }
 
-// Normalize returns a new vector with unit length and same direction as given vector
+// Normalize returns a new vector with unit length and same direction as given vector.
+// A zero vector has no direction, so it is returned unchanged.
 func Normalize(a Vector3) Vector3 {
-	length := a.Length()
+	length := Length(a)
+	if length == 0 {
+		return Vector3{}
+	}
 	return Vector3{a.X / length, a.Y / length, a.Z / length}
 }
+
+// Lerp linearly interpolates between two vectors
+func Lerp(a, b Vector3, pct float32) Vector3 {
+	return Vector3{
+		a.X + pct*(b.X-a.X),
+		a.Y + pct*(b.Y-a.Y),
+		a.Z + pct*(b.Z-a.Z),
+	}
+}
+
+// Reflect bounces a off a surface with unit normal n
+func Reflect(a, n Vector3) Vector3 {
+	return Sub(a, Mult(n, 2*Dot(a, n)))
+}
+
+// Project returns the part of a that points along b, it is zero if b is zero
+func Project(a, b Vector3) Vector3 {
+	lengthSquared := LengthSquared(b)
+	if lengthSquared == 0 {
+		return Vector3{}
+	}
+	return Mult(b, Dot(a, b)/lengthSquared)
+}
+
+// Angle returns the angle between two vectors in radians, it is zero if either is zero
+func Angle(a, b Vector3) float32 {
+	lengths := Length(a) * Length(b)
+	if lengths == 0 {
+		return 0
+	}
+	cos := Dot(a, b) / lengths
+	// rounding can push cos just outside -1 to 1
+	if cos > 1 {
+		cos = 1
+	} else if cos < -1 {
+		cos = -1
+	}
+	return float32(math.Acos(float64(cos)))
+}
+
+// Min returns the smallest of each component of two vectors
+func Min(a, b Vector3) Vector3 {
+	return Vector3{minf(a.X, b.X), minf(a.Y, b.Y), minf(a.Z, b.Z)}
+}
+
+// Max returns the largest of each component of two vectors
+func Max(a, b Vector3) Vector3 {
+	return Vector3{maxf(a.X, b.X), maxf(a.Y, b.Y), maxf(a.Z, b.Z)}
+}
+
+// Clamp limits each component of a to between the components of lo and hi
+func Clamp(a, lo, hi Vector3) Vector3 {
+	return Min(Max(a, lo), hi)
+}
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func ApproxEqual(a, b Vector3, epsilon float32) bool {
+	return abs(a.X-b.X) <= epsilon && abs(a.Y-b.Y) <= epsilon && abs(a.Z-b.Z) <= epsilon
+}
+
+func minf(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(a float32) float32 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Methods
+
+// Add returns a + b
+func (a Vector3) Add(b Vector3) Vector3 { return Add(a, b) }
+
+// Sub returns a - b
+func (a Vector3) Sub(b Vector3) Vector3 { return Sub(a, b) }
+
+// Neg returns a vector pointing the opposite way
+func (a Vector3) Neg() Vector3 { return Neg(a) }
+
+// Mult returns a multiplied by the scalar b
+func (a Vector3) Mult(b float32) Vector3 { return Mult(a, b) }
+
+// Scale returns a multiplied component-wise by b
+func (a Vector3) Scale(b Vector3) Vector3 { return Scale(a, b) }
+
+// Dot returns the dot product of a and b
+func (a Vector3) Dot(b Vector3) float32 { return Dot(a, b) }
+
+// Cross returns the cross product of a and b
+func (a Vector3) Cross(b Vector3) Vector3 { return Cross(a, b) }
+
+// LengthSquared returns the squared magnitude of the vector
+func (a Vector3) LengthSquared() float32 { return LengthSquared(a) }
+
+// Length returns the magnitude of the vector
+func (a Vector3) Length() float32 { return Length(a) }
+
+// Distance returns the distance from a to b
+func (a Vector3) Distance(b Vector3) float32 { return Distance(a, b) }
+
+// DistanceSquared returns the squared distance from a to b
+func (a Vector3) DistanceSquared(b Vector3) float32 { return DistanceSquared(a, b) }
+
+// Normalize returns the vector with unit length, a zero vector stays zero
+func (a Vector3) Normalize() Vector3 { return Normalize(a) }
+
+// Lerp linearly interpolates from a to b
+func (a Vector3) Lerp(b Vector3, pct float32) Vector3 { return Lerp(a, b, pct) }
+
+// Reflect bounces a off a surface with unit normal n
+func (a Vector3) Reflect(n Vector3) Vector3 { return Reflect(a, n) }
+
+// Project returns the part of a that points along b
+func (a Vector3) Project(b Vector3) Vector3 { return Project(a, b) }
+
+// Angle returns the angle between a and b in radians
+func (a Vector3) Angle(b Vector3) float32 { return Angle(a, b) }
+
+// Min returns the smallest of each component of a and b
+func (a Vector3) Min(b Vector3) Vector3 { return Min(a, b) }
+
+// Max returns the largest of each component of a and b
+func (a Vector3) Max(b Vector3) Vector3 { return Max(a, b) }
+
+// Clamp limits each component of a to between the components of lo and hi
+func (a Vector3) Clamp(lo, hi Vector3) Vector3 { return Clamp(a, lo, hi) }
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func (a Vector3) ApproxEqual(b Vector3, epsilon float32) bool { return ApproxEqual(a, b, epsilon) }
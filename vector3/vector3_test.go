@@ -0,0 +1,106 @@
+package vector3
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// vec is a Vector3 testing/quick fills with components between -100 and 100,
+// the full float32 range would just test overflow
+type vec Vector3
+
+func (vec) Generate(r *rand.Rand, size int) reflect.Value {
+	c := func() float32 { return r.Float32()*200 - 100 }
+	return reflect.ValueOf(vec{c(), c(), c()})
+}
+
+// near compares with a tolerance that grows with the size of the values involved
+func near(a, b, scale float32) bool {
+	return abs(a-b) <= 1e-4*(1+scale)
+}
+
+func nearVec(a, b Vector3, scale float32) bool {
+	return near(a.X, b.X, scale) && near(a.Y, b.Y, scale) && near(a.Z, b.Z, scale)
+}
+
+func check(t *testing.T, name string, f interface{}) {
+	t.Helper()
+	if err := quick.Check(f, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Errorf("%s: %v", name, err)
+	}
+}
+
+func TestCrossIdentities(t *testing.T) {
+	check(t, "a x b = -(b x a)", func(a, b vec) bool {
+		return nearVec(Cross(Vector3(a), Vector3(b)), Neg(Cross(Vector3(b), Vector3(a))), Length(Vector3(a))*Length(Vector3(b)))
+	})
+	check(t, "a x b is orthogonal to a and b", func(a, b vec) bool {
+		c := Cross(Vector3(a), Vector3(b))
+		scale := Length(Vector3(a)) * Length(Vector3(b)) * Length(c)
+		return near(Dot(c, Vector3(a)), 0, scale) && near(Dot(c, Vector3(b)), 0, scale)
+	})
+	check(t, "a x a = 0", func(a vec) bool {
+		return nearVec(Cross(Vector3(a), Vector3(a)), Vector3{}, LengthSquared(Vector3(a)))
+	})
+}
+
+func TestDotSymmetric(t *testing.T) {
+	check(t, "a . b = b . a", func(a, b vec) bool {
+		return near(Dot(Vector3(a), Vector3(b)), Dot(Vector3(b), Vector3(a)), Length(Vector3(a))*Length(Vector3(b)))
+	})
+	check(t, "a . a = |a|^2", func(a vec) bool {
+		return near(Dot(Vector3(a), Vector3(a)), LengthSquared(Vector3(a)), LengthSquared(Vector3(a)))
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	check(t, "|normalize(a)| = 1", func(a vec) bool {
+		if Vector3(a) == (Vector3{}) {
+			return true
+		}
+		return near(Length(Normalize(Vector3(a))), 1, 0)
+	})
+	if n := Normalize(Vector3{}); n != (Vector3{}) {
+		t.Errorf("normalized zero vector is %v, want zero", n)
+	}
+	if n := (Vector3{}).Normalize(); n != (Vector3{}) {
+		t.Errorf("normalized zero vector method is %v, want zero", n)
+	}
+}
+
+func TestReflectTwice(t *testing.T) {
+	check(t, "reflect(reflect(a, n), n) = a", func(a, n vec) bool {
+		if Vector3(n) == (Vector3{}) {
+			return true
+		}
+		unit := Normalize(Vector3(n))
+		return nearVec(Reflect(Reflect(Vector3(a), unit), unit), Vector3(a), Length(Vector3(a)))
+	})
+	check(t, "reflect keeps length", func(a, n vec) bool {
+		if Vector3(n) == (Vector3{}) {
+			return true
+		}
+		return near(Length(Reflect(Vector3(a), Normalize(Vector3(n)))), Length(Vector3(a)), Length(Vector3(a)))
+	})
+}
+
+func TestLerpEndpoints(t *testing.T) {
+	check(t, "lerp(a, b, 0) = a", func(a, b vec) bool {
+		return Lerp(Vector3(a), Vector3(b), 0) == Vector3(a)
+	})
+	check(t, "lerp(a, b, 1) = b", func(a, b vec) bool {
+		return nearVec(Lerp(Vector3(a), Vector3(b), 1), Vector3(b), Length(Vector3(a))+Length(Vector3(b)))
+	})
+}
+
+func TestLength(t *testing.T) {
+	if l := Length(Vector3{3, 4, 12}); l != 13 {
+		t.Errorf("length of 3, 4, 12 is %v, want 13", l)
+	}
+	check(t, "|a - b| = distance(a, b)", func(a, b vec) bool {
+		d := Distance(Vector3(a), Vector3(b))
+		return near(Length(Sub(Vector3(a), Vector3(b))), d, d)
+	})
+}
@@ -0,0 +1,263 @@
+package vector4
+
+import (
+	"math"
+
+	"github.com/sabith-th/games_with_go/vector2"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// Vector4 a 4d vector
+type Vector4 struct {
+	X, Y, Z, W float32
+}
+
+// Add adds two vectors and returns a new vector
+func Add(a, b Vector4) Vector4 {
+	return Vector4{a.X + b.X, a.Y + b.Y, a.Z + b.Z, a.W + b.W}
+}
+
+// Sub subtracts b from a and returns a new vector
+func Sub(a, b Vector4) Vector4 {
+	return Vector4{a.X - b.X, a.Y - b.Y, a.Z - b.Z, a.W - b.W}
+}
+
+// Neg returns a vector pointing the opposite way
+func Neg(a Vector4) Vector4 {
+	return Vector4{-a.X, -a.Y, -a.Z, -a.W}
+}
+
+// Mult multiplies a scalar to a vector and returns a new vector
+func Mult(a Vector4, b float32) Vector4 {
+	return Vector4{a.X * b, a.Y * b, a.Z * b, a.W * b}
+}
+
+// Scale multiplies each component of a by the matching component of b
+func Scale(a, b Vector4) Vector4 {
+	return Vector4{a.X * b.X, a.Y * b.Y, a.Z * b.Z, a.W * b.W}
+}
+
+// Dot returns the dot product of two vectors
+func Dot(a, b Vector4) float32 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z + a.W*b.W
+}
+
+// Cross returns the cross product of the xyz parts of two vectors, W is zero
+func Cross(a, b Vector4) Vector4 {
+	return Vector4{
+		a.Y*b.Z - a.Z*b.Y,
+		a.Z*b.X - a.X*b.Z,
+		a.X*b.Y - a.Y*b.X,
+		0,
+	}
+}
+
+// LengthSquared returns the squared magnitude of the given vector
+func LengthSquared(a Vector4) float32 {
+	return a.X*a.X + a.Y*a.Y + a.Z*a.Z + a.W*a.W
+}
+
+// Length returns the magnitude of the given vector
+func Length(a Vector4) float32 {
+	return float32(math.Sqrt(float64(LengthSquared(a))))
+}
+
+// Distance returns the distance between two vectors
+func Distance(a, b Vector4) float32 {
+	return Length(Sub(a, b))
+}
+
+// DistanceSquared returns the squared distance between two vectors
+func DistanceSquared(a, b Vector4) float32 {
+	return LengthSquared(Sub(a, b))
+}
+
+// Normalize returns a new vector with unit length and same direction as given vector.
+// A zero vector has no direction, so it is returned unchanged.
+func Normalize(a Vector4) Vector4 {
+	length := Length(a)
+	if length == 0 {
+		return Vector4{}
+	}
+	return Vector4{a.X / length, a.Y / length, a.Z / length, a.W / length}
+}
+
+// Lerp linearly interpolates between two vectors
+func Lerp(a, b Vector4, pct float32) Vector4 {
+	return Vector4{
+		a.X + pct*(b.X-a.X),
+		a.Y + pct*(b.Y-a.Y),
+		a.Z + pct*(b.Z-a.Z),
+		a.W + pct*(b.W-a.W),
+	}
+}
+
+// Reflect bounces a off a surface with unit normal n
+func Reflect(a, n Vector4) Vector4 {
+	return Sub(a, Mult(n, 2*Dot(a, n)))
+}
+
+// Project returns the part of a that points along b, it is zero if b is zero
+func Project(a, b Vector4) Vector4 {
+	lengthSquared := LengthSquared(b)
+	if lengthSquared == 0 {
+		return Vector4{}
+	}
+	return Mult(b, Dot(a, b)/lengthSquared)
+}
+
+// Angle returns the angle between two vectors in radians, it is zero if either is zero
+func Angle(a, b Vector4) float32 {
+	lengths := Length(a) * Length(b)
+	if lengths == 0 {
+		return 0
+	}
+	cos := Dot(a, b) / lengths
+	// rounding can push cos just outside -1 to 1
+	if cos > 1 {
+		cos = 1
+	} else if cos < -1 {
+		cos = -1
+	}
+	return float32(math.Acos(float64(cos)))
+}
+
+// Min returns the smallest of each component of two vectors
+func Min(a, b Vector4) Vector4 {
+	return Vector4{minf(a.X, b.X), minf(a.Y, b.Y), minf(a.Z, b.Z), minf(a.W, b.W)}
+}
+
+// Max returns the largest of each component of two vectors
+func Max(a, b Vector4) Vector4 {
+	return Vector4{maxf(a.X, b.X), maxf(a.Y, b.Y), maxf(a.Z, b.Z), maxf(a.W, b.W)}
+}
+
+// Clamp limits each component of a to between the components of lo and hi
+func Clamp(a, lo, hi Vector4) Vector4 {
+	return Min(Max(a, lo), hi)
+}
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func ApproxEqual(a, b Vector4, epsilon float32) bool {
+	return abs(a.X-b.X) <= epsilon && abs(a.Y-b.Y) <= epsilon && abs(a.Z-b.Z) <= epsilon && abs(a.W-b.W) <= epsilon
+}
+
+func minf(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(a float32) float32 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Conversions
+
+// FromVector2 returns a with z and w added
+func FromVector2(a vector2.Vector2, z, w float32) Vector4 {
+	return Vector4{a.X, a.Y, z, w}
+}
+
+// FromVector3 returns a with w added, use 1 for a point and 0 for a direction
+func FromVector3(a vector3.Vector3, w float32) Vector4 {
+	return Vector4{a.X, a.Y, a.Z, w}
+}
+
+// ToVector2 returns the x and y of a
+func ToVector2(a Vector4) vector2.Vector2 {
+	return vector2.Vector2{X: a.X, Y: a.Y}
+}
+
+// ToVector3 returns the x, y and z of a, dropping w
+func ToVector3(a Vector4) vector3.Vector3 {
+	return vector3.Vector3{X: a.X, Y: a.Y, Z: a.Z}
+}
+
+// PerspectiveDivide divides x, y and z by w, turning a homogeneous point back into 3d.
+// A zero w is a direction, so x, y and z are returned as they are.
+func PerspectiveDivide(a Vector4) vector3.Vector3 {
+	if a.W == 0 {
+		return ToVector3(a)
+	}
+	return vector3.Vector3{X: a.X / a.W, Y: a.Y / a.W, Z: a.Z / a.W}
+}
+
+// Methods
+
+// Add returns a + b
+func (a Vector4) Add(b Vector4) Vector4 { return Add(a, b) }
+
+// Sub returns a - b
+func (a Vector4) Sub(b Vector4) Vector4 { return Sub(a, b) }
+
+// Neg returns a vector pointing the opposite way
+func (a Vector4) Neg() Vector4 { return Neg(a) }
+
+// Mult returns a multiplied by the scalar b
+func (a Vector4) Mult(b float32) Vector4 { return Mult(a, b) }
+
+// Scale returns a multiplied component-wise by b
+func (a Vector4) Scale(b Vector4) Vector4 { return Scale(a, b) }
+
+// Dot returns the dot product of a and b
+func (a Vector4) Dot(b Vector4) float32 { return Dot(a, b) }
+
+// Cross returns the cross product of the xyz parts of a and b
+func (a Vector4) Cross(b Vector4) Vector4 { return Cross(a, b) }
+
+// LengthSquared returns the squared magnitude of the vector
+func (a Vector4) LengthSquared() float32 { return LengthSquared(a) }
+
+// Length returns the magnitude of the vector
+func (a Vector4) Length() float32 { return Length(a) }
+
+// Distance returns the distance from a to b
+func (a Vector4) Distance(b Vector4) float32 { return Distance(a, b) }
+
+// DistanceSquared returns the squared distance from a to b
+func (a Vector4) DistanceSquared(b Vector4) float32 { return DistanceSquared(a, b) }
+
+// Normalize returns the vector with unit length, a zero vector stays zero
+func (a Vector4) Normalize() Vector4 { return Normalize(a) }
+
+// Lerp linearly interpolates from a to b
+func (a Vector4) Lerp(b Vector4, pct float32) Vector4 { return Lerp(a, b, pct) }
+
+// Reflect bounces a off a surface with unit normal n
+func (a Vector4) Reflect(n Vector4) Vector4 { return Reflect(a, n) }
+
+// Project returns the part of a that points along b
+func (a Vector4) Project(b Vector4) Vector4 { return Project(a, b) }
+
+// Angle returns the angle between a and b in radians
+func (a Vector4) Angle(b Vector4) float32 { return Angle(a, b) }
+
+// Min returns the smallest of each component of a and b
+func (a Vector4) Min(b Vector4) Vector4 { return Min(a, b) }
+
+// Max returns the largest of each component of a and b
+func (a Vector4) Max(b Vector4) Vector4 { return Max(a, b) }
+
+// Clamp limits each component of a to between the components of lo and hi
+func (a Vector4) Clamp(lo, hi Vector4) Vector4 { return Clamp(a, lo, hi) }
+
+// ApproxEqual reports whether every component of a and b is within epsilon
+func (a Vector4) ApproxEqual(b Vector4, epsilon float32) bool { return ApproxEqual(a, b, epsilon) }
+
+// Vector2 returns the x and y of a
+func (a Vector4) Vector2() vector2.Vector2 { return ToVector2(a) }
+
+// Vector3 returns the x, y and z of a
+func (a Vector4) Vector3() vector3.Vector3 { return ToVector3(a) }
@@ -0,0 +1,112 @@
+package vector4
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/sabith-th/games_with_go/vector2"
+	"github.com/sabith-th/games_with_go/vector3"
+)
+
+// vec is a Vector4 testing/quick fills with components between -100 and 100,
+// the full float32 range would just test overflow
+type vec Vector4
+
+func (vec) Generate(r *rand.Rand, size int) reflect.Value {
+	c := func() float32 { return r.Float32()*200 - 100 }
+	return reflect.ValueOf(vec{c(), c(), c(), c()})
+}
+
+// near compares with a tolerance that grows with the size of the values involved
+func near(a, b, scale float32) bool {
+	return abs(a-b) <= 1e-4*(1+scale)
+}
+
+func nearVec(a, b Vector4, scale float32) bool {
+	return near(a.X, b.X, scale) && near(a.Y, b.Y, scale) && near(a.Z, b.Z, scale) && near(a.W, b.W, scale)
+}
+
+func check(t *testing.T, name string, f interface{}) {
+	t.Helper()
+	if err := quick.Check(f, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Errorf("%s: %v", name, err)
+	}
+}
+
+func TestDotSymmetric(t *testing.T) {
+	check(t, "a . b = b . a", func(a, b vec) bool {
+		return near(Dot(Vector4(a), Vector4(b)), Dot(Vector4(b), Vector4(a)), Length(Vector4(a))*Length(Vector4(b)))
+	})
+	check(t, "a . a = |a|^2", func(a vec) bool {
+		return near(Dot(Vector4(a), Vector4(a)), LengthSquared(Vector4(a)), LengthSquared(Vector4(a)))
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	check(t, "|normalize(a)| = 1", func(a vec) bool {
+		if Vector4(a) == (Vector4{}) {
+			return true
+		}
+		return near(Length(Normalize(Vector4(a))), 1, 0)
+	})
+	if n := Normalize(Vector4{}); n != (Vector4{}) {
+		t.Errorf("normalized zero vector is %v, want zero", n)
+	}
+	if n := (Vector4{}).Normalize(); n != (Vector4{}) {
+		t.Errorf("normalized zero vector method is %v, want zero", n)
+	}
+}
+
+func TestLerpEndpoints(t *testing.T) {
+	check(t, "lerp(a, b, 0) = a", func(a, b vec) bool {
+		return Lerp(Vector4(a), Vector4(b), 0) == Vector4(a)
+	})
+	check(t, "lerp(a, b, 1) = b", func(a, b vec) bool {
+		return nearVec(Lerp(Vector4(a), Vector4(b), 1), Vector4(b), Length(Vector4(a))+Length(Vector4(b)))
+	})
+}
+
+func TestLength(t *testing.T) {
+	if l := Length(Vector4{1, 2, 2, 4}); l != 5 {
+		t.Errorf("length of 1, 2, 2, 4 is %v, want 5", l)
+	}
+	check(t, "|a - b| = distance(a, b)", func(a, b vec) bool {
+		d := Distance(Vector4(a), Vector4(b))
+		return near(Length(Sub(Vector4(a), Vector4(b))), d, d)
+	})
+}
+
+func TestConversionsRoundTrip(t *testing.T) {
+	a := Vector4{1, -2, 3.5, 1}
+	if back := FromVector3(a.Vector3(), a.W); back != a {
+		t.Errorf("%v went through a Vector3 and came back as %v", a, back)
+	}
+	if back := FromVector2(a.Vector2(), a.Z, a.W); back != a {
+		t.Errorf("%v went through a Vector2 and came back as %v", a, back)
+	}
+	if v := a.Vector2(); v != (vector2.Vector2{X: 1, Y: -2}) {
+		t.Errorf("%v as a Vector2 is %v", a, v)
+	}
+}
+
+func TestPerspectiveDivide(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Vector4
+		want vector3.Vector3
+	}{
+		{"w of 1", Vector4{1, 2, 3, 1}, vector3.Vector3{X: 1, Y: 2, Z: 3}},
+		{"w of 2", Vector4{2, -4, 6, 2}, vector3.Vector3{X: 1, Y: -2, Z: 3}},
+		// behind the camera w is negative and flips every component
+		{"negative w", Vector4{2, -4, 6, -2}, vector3.Vector3{X: -1, Y: 2, Z: -3}},
+		// a direction has no position to divide back to
+		{"zero w", Vector4{2, -4, 6, 0}, vector3.Vector3{X: 2, Y: -4, Z: 6}},
+	}
+	for _, tt := range tests {
+		if got := PerspectiveDivide(tt.a); got != tt.want {
+			t.Errorf("%s: %v divides to %v, want %v", tt.name, tt.a, got, tt.want)
+		}
+	}
+}